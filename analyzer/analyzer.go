@@ -4,9 +4,9 @@ package analyzer
 import (
 	"flag"
 	"go/ast"
+	"reflect"
 
 	"golang.org/x/tools/go/analysis"
-	"golang.org/x/tools/go/analysis/passes/inspect"
 )
 
 // Configuration flags for detection techniques
@@ -18,20 +18,10 @@ var (
 	disableNamedCommentDetection     bool
 )
 
-// Configuration flags for definition constraints
+// Configuration flags for definition constraints (DC-005)
 var (
-	disableMinConstantsCheck   bool
-	disableSameBlockCheck      bool
-	disableSameFileCheck       bool
-	disableExclusiveBlockCheck bool
-	disableProximityCheck      bool
-)
-
-// Configuration flags for quality-of-life checks (US4-US6)
-var (
-	disableUint8Suggestion      bool
-	disableStringMethodCheck    bool
-	disableUnmarshalMethodCheck bool
+	proximityLines        int
+	proximityAllowMethods bool
 )
 
 // Configuration for detection keyword customization (FR-070, FR-131)
@@ -39,12 +29,14 @@ var enumKeyword string
 
 // Analyzer is the quasi-enum type safety analyzer.
 var Analyzer = &analysis.Analyzer{
-	Name:     "enumsafety",
-	Doc:      "check that quasi-enum types are only assigned their defined constants and satisfy definition constraints",
-	URL:      "https://github.com/Djarvur/go-enumsafety",
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
-	Run:      run,
-	Flags:    makeFlags(),
+	Name:       "enumsafety",
+	Doc:        "check that quasi-enum types are only assigned their defined constants and satisfy definition constraints",
+	URL:        "https://github.com/Djarvur/go-enumsafety/blob/master/docs/rules.md",
+	Requires:   []*analysis.Analyzer{detectAnalyzer},
+	Run:        run,
+	Flags:      makeFlags(),
+	ResultType: reflect.TypeOf((*Catalog)(nil)),
+	FactTypes:  []analysis.Fact{new(unvalidatedResultFact), new(constantUsageFact)},
 }
 
 // makeFlags creates and returns a flag.FlagSet with all analyzer flags.
@@ -64,29 +56,41 @@ func makeFlags() flag.FlagSet {
 		"disable DT-005: named comment detection")
 
 	// Definition constraint flags
-	fs.BoolVar(&disableMinConstantsCheck, "disable-min-constants-check", false,
-		"disable DC-001: minimum 2 constants check")
-	fs.BoolVar(&disableSameBlockCheck, "disable-same-block-check", false,
-		"disable DC-002: same const block check")
-	fs.BoolVar(&disableSameFileCheck, "disable-same-file-check", false,
-		"disable DC-003: same file check")
-	fs.BoolVar(&disableExclusiveBlockCheck, "disable-exclusive-block-check", false,
-		"disable DC-004: exclusive const block check")
-	fs.BoolVar(&disableProximityCheck, "disable-proximity-check", false,
-		"disable DC-005: proximity check")
-
-	// Quality-of-life check flags (US4-US6)
-	fs.BoolVar(&disableUint8Suggestion, "disable-uint8-suggestion", false,
-		"disable US4: uint8 optimization suggestion")
-	fs.BoolVar(&disableStringMethodCheck, "disable-string-method-check", false,
-		"disable US5: String() method check")
-	fs.BoolVar(&disableUnmarshalMethodCheck, "disable-unmarshal-method-check", false,
-		"disable US6: UnmarshalText() method check")
+	fs.IntVar(&proximityLines, "proximity-lines", 10,
+		"DC-005: maximum number of lines between type definition and const block (0: no limit)")
+	fs.BoolVar(&proximityAllowMethods, "proximity-allow-methods", false,
+		"DC-005: allow methods of the type between type definition and const block")
+
+	// Quality-of-life check flags
+	fs.StringVar(&formatInterfaces, "format-interfaces", defaultFormatInterfaces,
+		"US5: comma-separated interfaces (import/path.Name) accepted as a String() method")
+	fs.StringVar(&parseInterfaces, "parse-interfaces", defaultParseInterfaces,
+		"US6: comma-separated interfaces (import/path.Name) accepted as an UnmarshalText() method")
+	fs.StringVar(&persistInterfaces, "persist-interfaces", "",
+		"comma-separated interfaces (import/path.Name) of which quasi-enums must implement one (default: no check)")
+	fs.BoolVar(&checkValueHelpers, "value-helpers", false,
+		"report quasi-enums lacking IsValid() and Values()/All() methods")
+	fs.BoolVar(&checkUnused, "unused-constants", false,
+		"report quasi-enum constants never referenced outside their declaration and methods; "+
+			"exported constants of library packages are reported when analyzing main packages")
+	fs.BoolVar(&unusedAllowExported, "unused-allow-exported", false,
+		"-unused-constants: do not report exported constants of library packages")
 
 	// Keyword customization flag (FR-070, FR-131)
 	fs.StringVar(&enumKeyword, "enum-keyword", "enum",
 		"customize the detection keyword (default: 'enum')")
 
+	// Rule filter flags
+	fs.Var(filterFlag{&onlyRules}, "only",
+		"comma-separated category globs; report only matching rules (e.g. 'US*,DC-00[12]')")
+	fs.Var(filterFlag{&skipRules}, "skip",
+		"comma-separated category globs; suppress matching rules (e.g. 'QOL-*')")
+	fs.Var(filterFlag{&severityOverrides}, "severity",
+		"comma-separated category-glob=severity overrides (e.g. 'QOL-*=info,DC-005=error')")
+	for _, f := range deprecatedDisableFlags {
+		fs.Var(disableFlag{f.category}, f.name, "deprecated: use -skip="+f.category)
+	}
+
 	return fs
 }
 
@@ -97,34 +101,54 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		disableInlineCommentDetection && disablePrecedingCommentDetection &&
 		disableNamedCommentDetection {
 		// Report error and exit with code 2 (configuration error)
-		pass.Reportf(0, "all detection techniques disabled - no quasi-enums will be detected")
+		pass.Report(analysis.Diagnostic{
+			Category: CategoryConfiguration,
+			Message:  "all detection techniques disabled - no quasi-enums will be detected",
+		})
 		return nil, flag.ErrHelp // Signals configuration error
 	}
 
+	if err := validateRuleFilters(); err != nil {
+		return nil, err
+	}
+
 	// Create configuration
 	detectionConfig := NewDetectionConfig()
 	constraintConfig := NewConstraintConfig()
 
-	// Step 1: Detect quasi-enum types
-	detectedTypes := detectQuasiEnums(pass, detectionConfig)
-	if len(detectedTypes) == 0 {
-		// No quasi-enums detected, nothing to do
-		return nil, nil
+	// Step 1: Quasi-enum types of this package and its dependencies,
+	// detected with the declaration index by detectAnalyzer
+	det := pass.ResultOf[detectAnalyzer].(*detection)
+	idx := det.idx
+
+	// Uses of unvalidated results of other packages' functions (US3)
+	checkUnvalidatedResults(pass, idx)
+
+	if len(det.local) == 0 && len(det.imported) == 0 {
+		// No quasi-enums detected; only record references for main packages
+		checkUnusedConstants(pass, nil, idx)
+		return newCatalog(pass, nil, idx), nil
+	}
+
+	caps, err := resolveCapabilities(pass.Pkg)
+	if err != nil {
+		return nil, err
 	}
 
 	// Step 2: Build QuasiEnumRegistry
 	registry := NewQuasiEnumRegistry(detectionConfig, constraintConfig)
-
-	// For each detected type, collect constants and build QuasiEnumType
-	for namedType, techniques := range detectedTypes {
-		qe := buildQuasiEnumType(pass, namedType, techniques)
-		if qe != nil {
-			registry.RegisterQuasiEnum(qe)
-		}
+	for _, qe := range det.imported {
+		registry.RegisterQuasiEnum(qe)
 	}
+	for _, qe := range det.local {
+		// Detect helper methods (US5, US6)
+		detectHelperMethods(caps, qe)
+		registry.RegisterQuasiEnum(qe)
+	}
+	registerAliases(pass, registry, det)
 
 	// Step 3: Validate definition constraints
-	for _, qe := range registry.QuasiEnums {
+	for _, qe := range registry.LocalQuasiEnums() {
 		violations := qe.ValidateConstraints(
 			constraintConfig,
 			pass.Fset,
@@ -136,31 +160,42 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 		// Report constraint violations as warnings
 		for _, violation := range violations {
-			reportConstraintViolation(pass, qe, violation)
+			reportConstraintViolation(pass, registry, qe, violation)
 		}
 	}
 
-	// Step 4: Check for usage violations (US1, US2, US3)
-	for _, file := range pass.Files {
-		ast.Inspect(file, func(n ast.Node) bool {
-			switch node := n.(type) {
-			case *ast.AssignStmt:
-				checkAssignment(pass, registry, node)
-			case *ast.GenDecl:
-				checkVarDecl(pass, registry, node)
-			case *ast.CallExpr:
-				checkCallExpr(pass, registry, node)
-			case *ast.CompositeLit:
-				checkCompositeLit(pass, registry, node)
-			}
-			return true
-		})
+	// Mark functions returning unvalidated quasi-enum values for importers
+	exportUnvalidatedResults(pass, registry, idx)
+
+	// Step 4: Check for usage violations (US1, US2, US3, US7)
+	checkForeignConstants(pass, registry)
+	for _, n := range idx.usageNodes {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			checkAssignment(pass, registry, node)
+		case *ast.GenDecl:
+			checkVarDecl(pass, registry, node)
+		case *ast.CallExpr:
+			checkCallExpr(pass, registry, node)
+		case *ast.CompositeLit:
+			checkCompositeLit(pass, registry, node)
+		}
 	}
 
 	// Step 5: Check for quality-of-life improvements (US4, US5, US6)
-	checkUint8Optimization(pass, registry)
-	checkStringMethod(pass, registry)
-	checkUnmarshalTextMethod(pass, registry)
-
-	return nil, nil
+	compact := checkUint8Optimization(pass, registry)
+	checkStructLayout(pass, registry, idx, compact)
+	checkEnumMarker(pass, registry)
+	checkStringMethod(pass, registry, caps)
+	checkUnmarshalTextMethod(pass, registry, caps)
+	checkPersistMethods(pass, registry, caps)
+	checkValueHelperMethods(pass, registry)
+	checkStaleValueHelpers(pass, registry, idx)
+	checkMethodCoverage(pass, registry, idx)
+	checkStaleStringer(pass, registry, idx)
+	checkLookupTables(pass, registry, idx)
+	checkPersistence(pass, registry, idx, caps)
+	checkUnusedConstants(pass, registry, idx)
+
+	return newCatalog(pass, registry, idx), nil
 }
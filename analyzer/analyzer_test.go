@@ -1,10 +1,18 @@
 package analyzer
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -118,3 +126,430 @@ func TestCallExpressions(t *testing.T) {
 	testdata := filepath.Join(wd, "..", "testdata")
 	analysistest.Run(t, testdata, Analyzer, "call_expr")
 }
+
+// TestRuleFilters tests the -only and -skip category filters.
+func TestRuleFilters(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+
+	setFlag(t, "only", "US1-*,DC-*")
+	analysistest.Run(t, testdata, Analyzer, "rules_only")
+
+	setFlag(t, "only", "")
+	setFlag(t, "skip", "QOL-*,us3-*")
+	analysistest.Run(t, testdata, Analyzer, "rules_skip")
+}
+
+// TestDeprecatedDisableFlags tests that the deprecated disable-* flags skip their category.
+func TestDeprecatedDisableFlags(t *testing.T) {
+	for _, f := range deprecatedDisableFlags {
+		setFlag(t, f.name, "true")
+		if ruleEnabled(f.category) {
+			t.Errorf("-%s does not skip %s", f.name, f.category)
+		}
+		setFlag(t, f.name, "false")
+		if !ruleEnabled(f.category) {
+			t.Errorf("-%s=false skips %s", f.name, f.category)
+		}
+	}
+	if cfg := NewConstraintConfig(); !cfg.ProximityEnabled {
+		t.Error("DC-005 disabled by default")
+	}
+	setFlag(t, "skip", "DC-005")
+	if cfg := NewConstraintConfig(); cfg.ProximityEnabled {
+		t.Error("DC-005 enabled with -skip=DC-005")
+	}
+}
+
+// TestDiagnosticCategories tests that every diagnostic carries a stable rule category.
+func TestDiagnosticCategories(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	for _, result := range analysistest.Run(t, testdata, Analyzer, "a", "constraints_full") {
+		for _, diag := range result.Diagnostics {
+			if diag.Category == "" {
+				t.Errorf("diagnostic %q has no category", diag.Message)
+			}
+		}
+	}
+}
+
+// setFlag sets an analyzer flag for the duration of the test.
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+	old := Analyzer.Flags.Lookup(name).Value.String()
+	if err := Analyzer.Flags.Set(name, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := Analyzer.Flags.Set(name, old); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// TestSeverityOf tests default rule severities and -severity overrides.
+func TestSeverityOf(t *testing.T) {
+	tests := []struct {
+		overrides string
+		category  string
+		want      Severity
+	}{
+		{"", CategoryLiteralAssignment, SeverityError},
+		{"", CategoryExclusiveBlock, SeverityWarning},
+		{"", CategoryCompactType, SeverityInfo},
+		{"", CategoryStringMethod, SeverityWarning},
+		{"QOL-*=info", CategoryStringMethod, SeverityInfo},
+		{"DC-*=info,DC-005=error", CategoryProximity, SeverityError},
+		{"DC-*=info,DC-005=error", CategorySameFile, SeverityInfo},
+	}
+	for _, tt := range tests {
+		setFlag(t, "severity", tt.overrides)
+		if got := SeverityOf(tt.category); got != tt.want {
+			t.Errorf("SeverityOf(%q) with -severity=%q = %v, want %v", tt.category, tt.overrides, got, tt.want)
+		}
+	}
+
+	if _, err := parseSeverityOverrides("QOL-*=fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+	if _, err := parseSeverityOverrides("QOL-*"); err == nil {
+		t.Error("expected error for missing severity")
+	}
+}
+
+// TestDC002_SameConstBlockFix tests that stray constants are reported and moved into the primary block.
+func TestDC002_SameConstBlockFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix_dc002")
+}
+
+// TestDC005_ProximityFix tests that the const block is moved next to its type.
+func TestDC005_ProximityFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix_dc005")
+
+	setFlag(t, "proximity-lines", "3")
+	setFlag(t, "proximity-allow-methods", "true")
+	analysistest.Run(t, testdata, Analyzer, "proximity_config")
+}
+
+// TestDC004_ExclusiveBlockFix tests that mixed const blocks are split per enum type.
+func TestDC004_ExclusiveBlockFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix_dc004")
+}
+
+// TestDC003_SameFileFix tests that constants are moved into the file declaring their type.
+func TestDC003_SameFileFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix_dc003")
+}
+
+// TestEnumMarker tests the suggestion to mark types detected only by constants.
+func TestEnumMarker(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "enum_marker")
+}
+
+// TestCompactType tests the smallest-type suggestion and its fix on grouped declarations.
+func TestCompactType(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "compact_type")
+}
+
+// TestStructLayout tests the struct size check and its field reordering fix.
+func TestStructLayout(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "struct_layout")
+}
+
+// TestStructLayoutFixAlone applies only the fix of a struct layout
+// diagnostic and checks that it reaches the reported size on its own.
+func TestStructLayoutFixAlone(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	results := analysistest.Run(t, testdata, Analyzer, "struct_layout")
+
+	var fix *analysis.SuggestedFix
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == CategoryStructLayout && strings.HasPrefix(diag.Message, "struct Record ") && len(diag.SuggestedFixes) == 1 {
+			fix = &diag.SuggestedFixes[0]
+		}
+	}
+	if fix == nil {
+		t.Fatal("no fix for the layout of Record")
+	}
+
+	fset := results[0].Pass.Fset
+	name := fset.File(fix.TextEdits[0].Pos).Name()
+	src, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edits := slices.Clone(fix.TextEdits)
+	slices.SortFunc(edits, func(a, b analysis.TextEdit) int { return int(b.Pos - a.Pos) })
+	for _, edit := range edits {
+		from, to := fset.Position(edit.Pos).Offset, fset.Position(edit.End).Offset
+		src = append(src[:from:from], append(edit.NewText, src[to:]...)...)
+	}
+
+	fixed := token.NewFileSet()
+	file, err := parser.ParseFile(fixed, name, src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("struct_layout", fixed, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := pkg.Scope().Lookup("Record").Type()
+	if got := types.SizesFor("gc", "amd64").Sizeof(record); got != 24 {
+		t.Errorf("Record takes %d bytes after the fix %q, want 24", got, fix.Message)
+	}
+}
+
+// TestLookupTable tests the consistency checks of tables indexed by quasi-enums.
+func TestLookupTable(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "lookup_table")
+}
+
+// TestValueHelpers tests the generation of IsValid, Values and All and their staleness check.
+func TestValueHelpers(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	setFlag(t, "value-helpers", "true")
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "value_helpers")
+}
+
+// TestCatalog tests the catalog returned as the analyzer result.
+func TestCatalog(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	results := analysistest.Run(t, testdata, Analyzer, "fix_dc004")
+	catalog := results[0].Result.(*Catalog)
+
+	want := []CatalogEnum{
+		{Package: "fix_dc004", Name: "Kind", Underlying: "uint8", Constants: []CatalogConstant{{Name: "KindA", Value: "12", Doc: "kind a"}, {Name: "KindB", Value: "13"}}},
+		{Package: "fix_dc004", Name: "Mode", Underlying: "uint8", Constants: []CatalogConstant{{Name: "ModeRead", Value: "1"}, {Name: "ModeWrite", Value: "2"}}},
+		{Package: "fix_dc004", Name: "Status", Underlying: "uint8", Constants: []CatalogConstant{{Name: "StatusActive", Value: "0", Doc: "StatusActive is the default."}, {Name: "StatusInactive", Value: "1"}, {Name: "StatusPending", Value: "5", Doc: "pending"}}},
+	}
+	if !reflect.DeepEqual(catalog.Enums, want) {
+		t.Errorf("catalog = %+v, want %+v", catalog.Enums, want)
+	}
+}
+
+// TestCatalogDocs tests the names and doc comments recorded in the catalog.
+func TestCatalogDocs(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	results := analysistest.Run(t, testdata, Analyzer, "catalog")
+	catalog := results[0].Result.(*Catalog)
+
+	want := []CatalogEnum{{
+		Package: "catalog", Name: "Color", Underlying: "uint8", Doc: "Color is the color of a widget.",
+		Constants: []CatalogConstant{
+			{Name: "ColorRed", Value: "0", Text: "red", Doc: "ColorRed is the default color."},
+			{Name: "ColorGreen", Value: "1", Text: "green", Doc: "green, as grass"},
+			{Name: "ColorBlue", Value: "2", Text: "blue"},
+		},
+	}}
+	if !reflect.DeepEqual(catalog.Enums, want) {
+		t.Errorf("catalog = %+v, want %+v", catalog.Enums, want)
+	}
+}
+
+// TestPersistence tests detection of quasi-enums persisted without marshaling methods.
+func TestPersistence(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "persistence")
+}
+
+// TestMethodCoverage tests that String(), MarshalText() and UnmarshalText() handle every constant.
+func TestMethodCoverage(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "method_coverage")
+}
+
+// TestStaleStringer tests detection of stringer output missing constants.
+func TestStaleStringer(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "stringer_stale")
+}
+
+// TestHelperCapabilities tests the interfaces accepted by the helper-method checks.
+func TestHelperCapabilities(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "helper_capabilities")
+
+	setFlag(t, "format-interfaces", "fmt.Formatter")
+	setFlag(t, "parse-interfaces", "encoding.TextUnmarshaler")
+	setFlag(t, "persist-interfaces", "database/sql/driver.Valuer,encoding.TextMarshaler")
+	analysistest.Run(t, testdata, Analyzer, "helper_capabilities_config")
+}
+
+// TestUnvalidatedResults tests reporting of unvalidated quasi-enum results
+// of functions from other packages.
+func TestUnvalidatedResults(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "unvalidated/...")
+}
+
+// TestUnusedConstants tests reporting of unreferenced quasi-enum constants.
+func TestUnusedConstants(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+
+	// Whole-program reporting is limited to the main package's module
+	setFlag(t, "unused-constants", "true")
+	analysistest.Run(t, filepath.Join(testdata, "src", "unused"), Analyzer, "./...")
+
+	setFlag(t, "unused-allow-exported", "true")
+	analysistest.Run(t, filepath.Join(testdata, "src", "unused_exported"), Analyzer, "./...")
+}
+
+// TestForeignConstants tests that constants of imported quasi-enum types
+// declared outside their package are reported and never count as members.
+func TestForeignConstants(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, Analyzer, "foreign/...")
+}
+
+// TestAliases tests that constants re-exporting enum constants are accepted
+// and listed in the catalog.
+func TestAliases(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+
+	var facade *Catalog
+	for _, result := range analysistest.Run(t, testdata, Analyzer, "aliases/...") {
+		if result.Pass.Pkg.Path() == "aliases/facade" {
+			facade = result.Result.(*Catalog)
+		}
+	}
+	if facade == nil {
+		t.Fatal("no result for aliases/facade")
+	}
+
+	want := []CatalogAlias{
+		{Package: "aliases/facade", Name: "Current", EnumPackage: "aliases/models", Enum: "Status", Constant: "StatusActive"},
+		{Package: "aliases/facade", Name: "StatusActive", EnumPackage: "aliases/models", Enum: "Status", Constant: "StatusActive"},
+		{Package: "aliases/facade", Name: "StatusInactive", EnumPackage: "aliases/models", Enum: "Status", Constant: "StatusInactive"},
+	}
+	if !reflect.DeepEqual(facade.Aliases, want) {
+		t.Errorf("aliases = %+v, want %+v", facade.Aliases, want)
+	}
+}
+
+// TestTaint tests detection of untrusted input converted to quasi-enum types.
+func TestTaint(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "testdata")
+	analysistest.Run(t, testdata, TaintAnalyzer, "taint")
+}
+
+// TestSplitInterfaceName tests parsing of capability interface names.
+func TestSplitInterfaceName(t *testing.T) {
+	tests := []struct {
+		name, path, typeName string
+		ok                   bool
+	}{
+		{"fmt.Stringer", "fmt", "Stringer", true},
+		{"encoding/json.Unmarshaler", "encoding/json", "Unmarshaler", true},
+		{"gopkg.in/yaml.v3.Unmarshaler", "gopkg.in/yaml.v3", "Unmarshaler", true},
+		{"Stringer", "", "", false},
+		{"fmt.", "", "", false},
+		{"example.com/pkg", "", "", false},
+	}
+	for _, tt := range tests {
+		path, typeName, ok := splitInterfaceName(tt.name)
+		if path != tt.path || typeName != tt.typeName || ok != tt.ok {
+			t.Errorf("splitInterfaceName(%q) = %q, %q, %v, want %q, %q, %v",
+				tt.name, path, typeName, ok, tt.path, tt.typeName, tt.ok)
+		}
+	}
+}
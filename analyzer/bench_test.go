@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// BenchmarkRun measures detection and the analyzer on synthetic packages with one
+// quasi-enum and a few usages per file, so the number of enums grows with
+// the number of files.
+func BenchmarkRun(b *testing.B) {
+	for _, files := range []int{100, 1000, 4000} {
+		b.Run(fmt.Sprintf("files=%d", files), func(b *testing.B) {
+			pass := syntheticPass(b, files)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				det, err := detectAnalyzer.Run(pass)
+				if err != nil {
+					b.Fatal(err)
+				}
+				pass.ResultOf[detectAnalyzer] = det
+				if _, err := Analyzer.Run(pass); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// syntheticPass type-checks a generated package and returns a pass over it.
+func syntheticPass(tb testing.TB, files int) *analysis.Pass {
+	tb.Helper()
+
+	fset := token.NewFileSet()
+	var syntax []*ast.File
+	for i := range files {
+		f, err := parser.ParseFile(fset, fmt.Sprintf("file%d.go", i), syntheticFile(i), parser.ParseComments)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		syntax = append(syntax, f)
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("synthetic", fset, syntax, info)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return &analysis.Pass{
+		Analyzer:   Analyzer,
+		Fset:       fset,
+		Files:      syntax,
+		Pkg:        pkg,
+		TypesInfo:  info,
+		TypesSizes: types.SizesFor("gc", "amd64"),
+		ResultOf:   map[*analysis.Analyzer]interface{}{inspect.Analyzer: inspector.New(syntax)},
+		Report:     func(analysis.Diagnostic) {},
+
+		ImportObjectFact: func(types.Object, analysis.Fact) bool { return false },
+		ExportObjectFact: func(types.Object, analysis.Fact) {},
+		AllObjectFacts:   func() []analysis.ObjectFact { return nil },
+	}
+}
+
+// syntheticFile generates the source of the i-th file of a synthetic package.
+func syntheticFile(i int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "package synthetic\n\n")
+	fmt.Fprintf(&sb, "// Status%d enum\ntype Status%d int\n\n", i, i)
+	fmt.Fprintf(&sb, "const (\n\tStatus%dActive Status%d = iota\n\tStatus%dInactive\n\tStatus%dPending\n)\n\n", i, i, i, i)
+	fmt.Fprintf(&sb, "type Config%d struct {\n\tStatus Status%d\n\tName   string\n}\n\n", i, i)
+	fmt.Fprintf(&sb, "func use%d(x int) {\n", i)
+	fmt.Fprintf(&sb, "\tvar s Status%d = Status%dActive\n", i, i)
+	fmt.Fprintf(&sb, "\ts = Status%d(x)\n", i)
+	fmt.Fprintf(&sb, "\tc := Config%d{Status: s, Name: \"n\"}\n", i)
+	fmt.Fprintf(&sb, "\t_ = c\n}\n")
+	return sb.String()
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Catalog lists the quasi-enum types of a package with their constants.
+// It is the result of Analyzer, so that tools built on the analysis
+// pipeline (such as cmd/enumdiff) can inspect enum definitions.
+type Catalog struct {
+	Enums   []CatalogEnum  `json:"enums"`
+	Aliases []CatalogAlias `json:"aliases,omitempty"`
+
+	registry *QuasiEnumRegistry // for analyzers requiring Analyzer
+}
+
+// CatalogEnum describes a quasi-enum type.
+type CatalogEnum struct {
+	Package    string            `json:"package"`       // Package path
+	Name       string            `json:"name"`          // Type name
+	Underlying string            `json:"underlying"`    // Underlying type, e.g. "uint8"
+	Doc        string            `json:"doc,omitempty"` // Doc comment of the type
+	Constants  []CatalogConstant `json:"constants"`     // Constants in declaration order
+}
+
+// CatalogConstant describes a constant of a quasi-enum type.
+type CatalogConstant struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`          // Exact Go representation of the value
+	Text  string `json:"text,omitempty"` // Name produced by MarshalText or String, if known
+	Doc   string `json:"doc,omitempty"`  // Doc comment, or line comment, of the constant
+}
+
+// CatalogAlias describes a constant re-exporting a constant of a quasi-enum
+// type declared in another package.
+type CatalogAlias struct {
+	Package     string `json:"package"`     // Package path of the alias
+	Name        string `json:"name"`        // Alias name
+	EnumPackage string `json:"enumPackage"` // Package path of the quasi-enum type
+	Enum        string `json:"enum"`        // Type name of the quasi-enum
+	Constant    string `json:"constant"`    // Name of the aliased constant
+}
+
+// newCatalog builds the catalog of the quasi-enums in the registry, sorted by type name.
+func newCatalog(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) *Catalog {
+	catalog := &Catalog{Enums: []CatalogEnum{}, registry: registry}
+	if registry == nil {
+		return catalog
+	}
+
+	for _, qe := range registry.LocalQuasiEnums() {
+		enum := CatalogEnum{
+			Package:    qe.PackagePath,
+			Name:       qe.Type.Obj().Name(),
+			Underlying: types.TypeString(qe.Type.Underlying(), nil),
+			Constants:  make([]CatalogConstant, 0, len(qe.Constants)),
+		}
+		if qe.TypeDecl != nil {
+			if spec := typeSpecOf(qe); spec != nil {
+				doc := spec.Doc
+				if doc == nil && len(qe.TypeDecl.Specs) == 1 {
+					doc = qe.TypeDecl.Doc
+				}
+				enum.Doc = docText(doc, enum.Name)
+			}
+		}
+		names := encoderNames(pass, idx, qe)
+		for _, c := range qe.Constants {
+			constant := CatalogConstant{Name: c.Name, Value: c.Value.ExactString(), Text: names[c.Value.ExactString()]}
+			if spec := specOf(c); spec != nil {
+				doc := spec.Doc
+				if doc == nil {
+					doc = spec.Comment
+				}
+				constant.Doc = docText(doc, "")
+			}
+			enum.Constants = append(enum.Constants, constant)
+		}
+		catalog.Enums = append(catalog.Enums, enum)
+	}
+	for c, member := range registry.Aliases {
+		if c.Parent() != pass.Pkg.Scope() {
+			continue // listed by the catalog of its own package, or declared in a function
+		}
+		enum := member.QuasiEnumType.Obj()
+		catalog.Aliases = append(catalog.Aliases, CatalogAlias{
+			Package:     c.Pkg().Path(),
+			Name:        c.Name(),
+			EnumPackage: enum.Pkg().Path(),
+			Enum:        enum.Name(),
+			Constant:    member.Name,
+		})
+	}
+	catalog.Sort()
+	return catalog
+}
+
+// docText returns the text of a comment group as a single line, without
+// directives and without "<typeName> enum" marker lines.
+func docText(group *ast.CommentGroup, typeName string) string {
+	marker := strings.ToLower(typeName + " " + enumKeyword)
+	var lines []string
+	for _, line := range strings.Split(group.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && strings.ToLower(line) != marker {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// Merge appends the enums and aliases of other catalogs, keeping the result sorted.
+func (c *Catalog) Merge(others ...*Catalog) {
+	for _, other := range others {
+		if other != nil {
+			c.Enums = append(c.Enums, other.Enums...)
+			c.Aliases = append(c.Aliases, other.Aliases...)
+		}
+	}
+	c.Sort()
+}
+
+// Sort orders the enums and aliases by package path and name.
+func (c *Catalog) Sort() {
+	sort.Slice(c.Enums, func(i, j int) bool {
+		if c.Enums[i].Package != c.Enums[j].Package {
+			return c.Enums[i].Package < c.Enums[j].Package
+		}
+		return c.Enums[i].Name < c.Enums[j].Name
+	})
+	sort.Slice(c.Aliases, func(i, j int) bool {
+		if c.Aliases[i].Package != c.Aliases[j].Package {
+			return c.Aliases[i].Package < c.Aliases[j].Package
+		}
+		return c.Aliases[i].Name < c.Aliases[j].Name
+	})
+}
@@ -0,0 +1,431 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// sameConstBlockFix moves the constants declared outside the primary const
+// block into it (DC-002). It returns nil when moving them could change the
+// value of a constant left behind.
+func sameConstBlockFix(pass *analysis.Pass, qe *QuasiEnumType) *analysis.SuggestedFix {
+	strays := strayConstants(qe)
+	if len(strays) == 0 || qe.ConstBlock == nil {
+		return nil
+	}
+
+	removals, specs, ok := removeEnumSpecs(pass, qe, strays)
+	if !ok {
+		return nil
+	}
+
+	var moved []string
+	for _, spec := range specs {
+		text, ok := movedSpec(pass, spec.decl, spec.spec, "\t")
+		if !ok {
+			return nil
+		}
+		moved = append(moved, text)
+	}
+
+	insertions, ok := insertIntoConstBlock(pass, qe.ConstBlock, moved)
+	if !ok {
+		return nil
+	}
+
+	return &analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Move stray constants of %s into its primary const block", qe.Type.Obj().Name()),
+		TextEdits: append(removals, insertions...),
+	}
+}
+
+// proximityFix moves the const block directly after the type declaration (DC-005).
+// It returns nil while constants are still scattered over several blocks or
+// share the block with other constants, as the DC-002 and DC-004 fixes edit
+// the same block.
+func proximityFix(pass *analysis.Pass, qe *QuasiEnumType) *analysis.SuggestedFix {
+	if qe.TypeDecl == nil || qe.ConstBlock == nil || len(strayConstants(qe)) > 0 ||
+		!validateExclusiveBlock(qe, qe.ConstBlock, pass.TypesInfo) {
+		return nil
+	}
+
+	from, to := declRange(qe.ConstBlock)
+	text, ok := sourceText(pass, from, to)
+	if !ok {
+		return nil
+	}
+
+	// Insert after the line ending the type declaration (and its line comment)
+	insertAt := nextLineStart(pass.Fset, qe.TypeDecl.End())
+	return &analysis.SuggestedFix{
+		Message: fmt.Sprintf("Move const block of %s after its type declaration", qe.Type.Obj().Name()),
+		TextEdits: []analysis.TextEdit{
+			deleteLines(pass.Fset, from, to),
+			{Pos: insertAt, End: insertAt, NewText: []byte("\n" + text + "\n")},
+		},
+	}
+}
+
+// sameFileFix moves the constants of a quasi-enum into the file declaring
+// the type, directly after the type declaration (DC-003). An exclusive block
+// is moved as a whole; otherwise the enum's specs are moved into a new block.
+// Imports used by the moved specs are added to the type's file, and removed
+// from the constants' file if nothing else uses them. It returns nil while
+// constants are scattered over several blocks (DC-002) or when an import
+// cannot be carried over (see importEdits).
+func sameFileFix(pass *analysis.Pass, qe *QuasiEnumType) *analysis.SuggestedFix {
+	if qe.TypeDecl == nil || qe.ConstBlock == nil || len(strayConstants(qe)) > 0 {
+		return nil
+	}
+
+	var removals []analysis.TextEdit
+	var moved []ast.Node
+	var text string
+	if validateExclusiveBlock(qe, qe.ConstBlock, pass.TypesInfo) {
+		moved = append(moved, qe.ConstBlock)
+		from, to := declRange(qe.ConstBlock)
+		var ok bool
+		if text, ok = sourceText(pass, from, to); !ok {
+			return nil
+		}
+		removals = append(removals, deleteLines(pass.Fset, from, to))
+	} else {
+		edits, specs, ok := removeEnumSpecs(pass, qe, qe.Constants)
+		if !ok {
+			return nil
+		}
+		var sb strings.Builder
+		sb.WriteString("const (\n")
+		for _, spec := range specs {
+			moved = append(moved, spec.spec)
+			line, ok := movedSpec(pass, spec.decl, spec.spec, "\t")
+			if !ok {
+				return nil
+			}
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		sb.WriteString(")")
+		removals, text = edits, sb.String()
+	}
+
+	imports, ok := importEdits(pass, fileAt(pass, qe.ConstBlock.Pos()), fileAt(pass, qe.TypeDecl.Pos()), moved)
+	if !ok {
+		return nil
+	}
+
+	insertAt := nextLineStart(pass.Fset, qe.TypeDecl.End())
+	edits := append(removals, imports...)
+	return &analysis.SuggestedFix{
+		Message: fmt.Sprintf("Move constants of %s after its type declaration", qe.Type.Obj().Name()),
+		TextEdits: append(edits, analysis.TextEdit{
+			Pos:     insertAt,
+			End:     insertAt,
+			NewText: []byte("\n" + text + "\n"),
+		}),
+	}
+}
+
+// importEdits returns the edits carrying the imports used by nodes moved
+// from one file to another: imports missing from the destination are added,
+// and imports of the source left unused are removed. It returns false for
+// renamed, dot and blank imports, and for packages the destination imports
+// under another name.
+func importEdits(pass *analysis.Pass, from, to *ast.File, moved []ast.Node) ([]analysis.TextEdit, bool) {
+	if from == nil || to == nil {
+		return nil, false
+	}
+
+	// The imports used by the moved nodes, in order of first use
+	var used []*types.PkgName
+	for _, node := range moved {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok {
+				if pkg, ok := pass.TypesInfo.Uses[ident].(*types.PkgName); ok && !slices.Contains(used, pkg) {
+					used = append(used, pkg)
+				}
+			}
+			return true
+		})
+	}
+
+	var edits []analysis.TextEdit
+	for _, pkg := range used {
+		path := pkg.Imported().Path()
+		if pkg.Name() != pkg.Imported().Name() {
+			return nil, false
+		}
+		for _, spec := range to.Imports {
+			if other := pass.TypesInfo.PkgNameOf(spec); other != nil && other.Imported() == pkg.Imported() && other.Name() != pkg.Name() {
+				return nil, false
+			}
+		}
+		if edit, ok := importEdit(pass.Fset, to, path); ok {
+			edits = append(edits, edit)
+		}
+		if !usedOutside(pass.TypesInfo, from, pkg, moved) {
+			edits = append(edits, removeImport(pass, from, pkg)...)
+		}
+	}
+	return edits, true
+}
+
+// removeImport returns the edit deleting the import of pkg from the file,
+// with its declaration unless the declaration is parenthesized.
+func removeImport(pass *analysis.Pass, file *ast.File, pkg *types.PkgName) []analysis.TextEdit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if pass.TypesInfo.PkgNameOf(spec.(*ast.ImportSpec)) != pkg {
+				continue
+			}
+			if !gen.Lparen.IsValid() {
+				return []analysis.TextEdit{deleteLines(pass.Fset, gen.Pos(), gen.End())}
+			}
+			return []analysis.TextEdit{deleteLines(pass.Fset, spec.Pos(), spec.End())}
+		}
+	}
+	return nil
+}
+
+// usedOutside reports whether the file refers to the imported package
+// outside the moved nodes.
+func usedOutside(info *types.Info, file *ast.File, pkg *types.PkgName, moved []ast.Node) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found || n == nil {
+			return false
+		}
+		if slices.Contains(moved, n) {
+			return false
+		}
+		if ident, ok := n.(*ast.Ident); ok && info.Uses[ident] == pkg {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// exclusiveBlockFix splits a mixed const block into one block per enum type
+// followed by a block for the remaining constants (DC-004). Groups keep the
+// order of their first appearance; specs whose iota or implicit value depends
+// on their position are materialized. It returns nil when a spec mixes
+// groups, when the block holds comments not attached to a spec, or while
+// constants of its enums are still to be moved to another block (DC-002) or
+// to the file declaring their type (DC-003).
+func exclusiveBlockFix(pass *analysis.Pass, registry *QuasiEnumRegistry, decl *ast.GenDecl) *analysis.SuggestedFix {
+	if decl == nil || !decl.Lparen.IsValid() || hasFloatingComments(pass, decl) {
+		return nil
+	}
+
+	// Group the specs; the nil key collects the non-enum constants
+	var order []*types.Named
+	groups := make(map[*types.Named][]int)
+	for i, s := range decl.Specs {
+		key, ok := specGroup(pass.TypesInfo, registry, s.(*ast.ValueSpec))
+		if !ok {
+			return nil
+		}
+		if key != nil {
+			qe := registry.QuasiEnums[key]
+			if len(strayConstants(qe)) > 0 || pass.Fset.File(qe.TypeDef.Pos()) != pass.Fset.File(decl.Pos()) {
+				return nil
+			}
+		}
+		if _, seen := groups[key]; !seen && key != nil {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	// The rest goes last
+	if _, ok := groups[nil]; ok {
+		order = append(order, nil)
+	}
+	if len(order) < 2 {
+		return nil
+	}
+
+	var blocks []string
+	for _, key := range order {
+		var sb strings.Builder
+		sb.WriteString("const (\n")
+		for newIndex, oldIndex := range groups[key] {
+			spec := decl.Specs[oldIndex].(*ast.ValueSpec)
+			text, ok := splitSpec(pass, spec, newIndex == oldIndex)
+			if !ok {
+				return nil
+			}
+			sb.WriteString(text)
+			sb.WriteString("\n")
+		}
+		sb.WriteString(")")
+		blocks = append(blocks, sb.String())
+	}
+
+	return &analysis.SuggestedFix{
+		Message: "Split const block into one block per enum type",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     decl.Pos(),
+			End:     decl.End(),
+			NewText: []byte(strings.Join(blocks, "\n\n")),
+		}},
+	}
+}
+
+// specGroup returns the enum type all names of a const spec belong to, or
+// nil if none of them is an enum constant. It fails for specs mixing both.
+func specGroup(info *types.Info, registry *QuasiEnumRegistry, spec *ast.ValueSpec) (*types.Named, bool) {
+	var group *types.Named
+	for i, name := range spec.Names {
+		var named *types.Named
+		if obj := info.Defs[name]; obj != nil && registry.IsQuasiEnumType(obj.Type()) {
+			named = obj.Type().(*types.Named)
+		}
+		if i > 0 && named != group {
+			return nil, false
+		}
+		group = named
+	}
+	return group, true
+}
+
+// splitSpec renders a const spec for a split block. Specs keeping their
+// index, and specs with explicit values not using iota, are copied verbatim.
+func splitSpec(pass *analysis.Pass, spec *ast.ValueSpec, sameIndex bool) (string, bool) {
+	if !sameIndex && (len(spec.Values) == 0 || usesIota(pass.TypesInfo, spec)) {
+		return materializedSpec(pass, spec, spec.Doc, "\t"), true
+	}
+	from, to := specRange(spec)
+	text, ok := sourceText(pass, from, to)
+	if !ok {
+		return "", false
+	}
+	return "\t" + reindent(text, "\t"), true
+}
+
+// hasFloatingComments reports whether a const block holds comments that are
+// not the doc or line comment of one of its specs.
+func hasFloatingComments(pass *analysis.Pass, decl *ast.GenDecl) bool {
+	attached := make(map[*ast.CommentGroup]bool)
+	for _, s := range decl.Specs {
+		spec := s.(*ast.ValueSpec)
+		attached[spec.Doc] = true
+		attached[spec.Comment] = true
+	}
+	for _, file := range pass.Files {
+		if file.FileStart > decl.Pos() || decl.End() > file.FileEnd {
+			continue
+		}
+		for _, group := range file.Comments {
+			if group.Pos() > decl.Lparen && group.End() < decl.Rparen && !attached[group] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeEnumSpecs returns the edits deleting the const specs that declare
+// the given constants, together with those specs in source order. Blocks
+// left empty are deleted entirely. It fails if a spec also declares other
+// constants, or if a remaining spec of the block depends on its position
+// (implicit values or iota).
+func removeEnumSpecs(pass *analysis.Pass, qe *QuasiEnumType, consts []EnumConstant) ([]analysis.TextEdit, []declSpec, bool) {
+	// Group the specs to remove by declaration, preserving source order
+	var decls []*ast.GenDecl
+	remove := make(map[*ast.ValueSpec]bool)
+	for _, c := range consts {
+		spec := specOf(c)
+		if spec == nil {
+			return nil, nil, false
+		}
+		if !remove[spec] {
+			if !specDeclaresOnly(pass.TypesInfo, spec, qe.Type) {
+				return nil, nil, false
+			}
+			if len(decls) == 0 || decls[len(decls)-1] != c.ConstBlock {
+				decls = append(decls, c.ConstBlock)
+			}
+		}
+		remove[spec] = true
+	}
+
+	var edits []analysis.TextEdit
+	var specs []declSpec
+	for _, decl := range decls {
+		removed, kept := 0, 0
+		for _, s := range decl.Specs {
+			spec := s.(*ast.ValueSpec)
+			if remove[spec] {
+				removed++
+				specs = append(specs, declSpec{decl, spec})
+				continue
+			}
+			// A kept spec after a removed one must not depend on its position
+			if removed > 0 && (len(spec.Values) == 0 || usesIota(pass.TypesInfo, spec)) {
+				return nil, nil, false
+			}
+			kept++
+		}
+
+		if kept == 0 {
+			from, to := declRange(decl)
+			edits = append(edits, deleteLines(pass.Fset, from, to))
+			continue
+		}
+		for _, s := range decl.Specs {
+			if spec := s.(*ast.ValueSpec); remove[spec] {
+				from, to := specRange(spec)
+				edits = append(edits, deleteLines(pass.Fset, from, to))
+			}
+		}
+	}
+
+	return edits, specs, true
+}
+
+// declSpec is a const spec together with its enclosing declaration.
+type declSpec struct {
+	decl *ast.GenDecl
+	spec *ast.ValueSpec
+}
+
+// specOf returns the value spec declaring an enum constant.
+func specOf(c EnumConstant) *ast.ValueSpec {
+	if c.ConstBlock == nil {
+		return nil
+	}
+	for _, s := range c.ConstBlock.Specs {
+		spec, ok := s.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, name := range spec.Names {
+			if name.Pos() == c.Position {
+				return spec
+			}
+		}
+	}
+	return nil
+}
+
+// specDeclaresOnly reports whether every name of the spec is a constant of the given type.
+func specDeclaresOnly(info *types.Info, spec *ast.ValueSpec, typ types.Type) bool {
+	for _, name := range spec.Names {
+		obj := info.Defs[name]
+		if obj == nil || obj.Type() != typ {
+			return false
+		}
+	}
+	return true
+}
@@ -2,9 +2,11 @@
 package analyzer
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"sort"
 )
 
 // validateMinConstants implements DC-001: minimum 2 constants check.
@@ -15,29 +17,19 @@ func validateMinConstants(qe *QuasiEnumType) bool {
 
 // validateSameConstBlock implements DC-002: same const block check.
 // Returns true if all constants are defined in the same const block.
-func validateSameConstBlock(qe *QuasiEnumType, fset *token.FileSet) bool {
-	if len(qe.Constants) < 2 {
-		return true // Trivially satisfied if < 2 constants
-	}
-
-	// Get the first constant's const block position
-	firstConst := qe.Constants[0]
-	firstPos := fset.Position(firstConst.Position)
+func validateSameConstBlock(qe *QuasiEnumType) bool {
+	return len(strayConstants(qe)) == 0
+}
 
-	// Check if all other constants are in the same const block
-	// We determine this by checking if they're all part of the same GenDecl
-	for i := 1; i < len(qe.Constants); i++ {
-		constPos := fset.Position(qe.Constants[i].Position)
-		// If file differs, they're definitely not in the same block
-		if constPos.Filename != firstPos.Filename {
-			return false
+// strayConstants returns the constants declared outside the primary const block (DC-002).
+func strayConstants(qe *QuasiEnumType) []EnumConstant {
+	var strays []EnumConstant
+	for _, c := range qe.Constants {
+		if c.ConstBlock != qe.ConstBlock {
+			strays = append(strays, c)
 		}
 	}
-
-	// Additional check: verify they're all in the same GenDecl
-	// This is done by checking if their parent GenDecl nodes are the same
-	// Note: This requires access to AST nodes, which we'll handle in the caller
-	return true
+	return strays
 }
 
 // validateSameFile implements DC-003: same file check.
@@ -100,36 +92,138 @@ func validateExclusiveBlock(qe *QuasiEnumType, constDecl *ast.GenDecl, info *typ
 	return true
 }
 
+// proximityIssue describes why a quasi-enum violates DC-005.
+type proximityIssue int
+
+const (
+	proximityOK proximityIssue = iota
+	proximityOtherFile
+	proximityDeclBetween
+	proximityTooFar
+)
+
 // validateProximity implements DC-005: proximity check.
 // Returns true if type definition and const block are adjacent (allowing comments/empty lines).
-func validateProximity(qe *QuasiEnumType, typeDecl *ast.GenDecl, constDecl *ast.GenDecl, fset *token.FileSet, file *ast.File) bool {
+func validateProximity(
+	qe *QuasiEnumType,
+	config *ConstraintConfig,
+	typeDecl *ast.GenDecl,
+	constDecl *ast.GenDecl,
+	fset *token.FileSet,
+	file *ast.File,
+	info *types.Info,
+) bool {
+	issue, _, _ := checkProximity(qe, config, typeDecl, constDecl, fset, file, info)
+	return issue == proximityOK
+}
+
+// checkProximity checks DC-005 and returns the issue found, if any, with the
+// number of lines between the type definition and the const block and, for
+// proximityDeclBetween, the first declaration sitting between them.
+// The const block may precede or follow the type definition.
+func checkProximity(
+	qe *QuasiEnumType,
+	config *ConstraintConfig,
+	typeDecl *ast.GenDecl,
+	constDecl *ast.GenDecl,
+	fset *token.FileSet,
+	file *ast.File,
+	info *types.Info,
+) (proximityIssue, int, ast.Decl) {
 	if typeDecl == nil || constDecl == nil {
-		return true
+		return proximityOK, 0, nil
 	}
 
-	// Get positions
-	typeEnd := fset.Position(typeDecl.End())
-	constStart := fset.Position(constDecl.Pos())
-
 	// Must be in the same file
-	if typeEnd.Filename != constStart.Filename {
-		return false
+	if fset.File(typeDecl.Pos()) != fset.File(constDecl.Pos()) {
+		return proximityOtherFile, 0, nil
+	}
+
+	first, second := ast.Node(typeDecl), ast.Node(constDecl)
+	if constDecl.Pos() < typeDecl.Pos() {
+		first, second = constDecl, typeDecl
 	}
 
 	// Find any code between type and const declarations
-	// We allow comments and empty lines, but no executable code
-	for _, decl := range file.Decls {
-		declStart := fset.Position(decl.Pos())
-		declEnd := fset.Position(decl.End())
-
-		// Check if this declaration is between type and const
-		if declStart.Offset > typeEnd.Offset && declEnd.Offset < constStart.Offset {
-			// Found a declaration between type and const
-			return false
+	// We allow comments and empty lines, but no executable code.
+	// file.Decls is in source order, so the search starts at the first
+	// declaration after the earlier of the two.
+	next := sort.Search(len(file.Decls), func(i int) bool {
+		return file.Decls[i].Pos() > first.End()
+	})
+	// Allowed methods in between do not count towards the distance.
+	after := first.End()
+	for _, decl := range file.Decls[next:] {
+		if decl.End() >= second.Pos() {
+			break
 		}
+		if config.ProximityAllowMethods && isMethodOf(decl, qe.Type, info) {
+			after = decl.End()
+			continue
+		}
+		// Found a declaration between type and const
+		return proximityDeclBetween, 0, decl
 	}
 
-	return true
+	// Doc comments of the later declaration count as part of it
+	secondStart := second.Pos()
+	if doc := second.(*ast.GenDecl).Doc; doc != nil {
+		secondStart = doc.Pos()
+	}
+	gap := fset.Position(secondStart).Line - fset.Position(after).Line - 1
+	if config.ProximityLines > 0 && gap > config.ProximityLines {
+		return proximityTooFar, gap, nil
+	}
+
+	return proximityOK, gap, nil
+}
+
+// describeDecl names a declaration for messages, e.g. "func helper",
+// "method Status.String" or "var defaults".
+func describeDecl(decl ast.Decl, info *types.Info) string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if fn, ok := info.Defs[decl.Name].(*types.Func); ok {
+			if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+				t := recv.Type()
+				if ptr, ok := t.(*types.Pointer); ok {
+					t = ptr.Elem()
+				}
+				if named := asNamed(t); named != nil {
+					return fmt.Sprintf("method %s.%s", named.Obj().Name(), decl.Name.Name)
+				}
+			}
+		}
+		return "func " + decl.Name.Name
+	case *ast.GenDecl:
+		if len(decl.Specs) > 0 {
+			switch spec := decl.Specs[0].(type) {
+			case *ast.TypeSpec:
+				return "type " + spec.Name.Name
+			case *ast.ValueSpec:
+				return decl.Tok.String() + " " + spec.Names[0].Name
+			}
+		}
+		return decl.Tok.String() + " declaration"
+	}
+	return "declaration"
+}
+
+// isMethodOf reports whether decl declares a method of the named type.
+func isMethodOf(decl ast.Decl, named *types.Named, info *types.Info) bool {
+	funcDecl, ok := decl.(*ast.FuncDecl)
+	if !ok || funcDecl.Recv == nil {
+		return false
+	}
+	fn, ok := info.Defs[funcDecl.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	return recv == named
 }
 
 // ValidateConstraints validates all enabled constraints for a quasi-enum type.
@@ -150,7 +244,7 @@ func (qe *QuasiEnumType) ValidateConstraints(
 	}
 
 	// DC-002: Same Const Block
-	if config.SameConstBlockEnabled && !validateSameConstBlock(qe, fset) {
+	if config.SameConstBlockEnabled && !validateSameConstBlock(qe) {
 		violations = append(violations, DC002SameConstBlock)
 	}
 
@@ -165,7 +259,7 @@ func (qe *QuasiEnumType) ValidateConstraints(
 	}
 
 	// DC-005: Proximity
-	if config.ProximityEnabled && !validateProximity(qe, typeDecl, constDecl, fset, file) {
+	if config.ProximityEnabled && !validateProximity(qe, config, typeDecl, constDecl, fset, file, info) {
 		violations = append(violations, DC005Proximity)
 	}
 
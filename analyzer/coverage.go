@@ -0,0 +1,355 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// methodMapping is the mapping between constants and names implemented by a
+// String, MarshalText or UnmarshalText method, as far as it can be read
+// from a switch statement or a keyed map or array literal.
+type methodMapping struct {
+	decl   *ast.FuncDecl
+	values map[string]bool // exact values of the constants handled
+	// For String and MarshalText: value -> produced name.
+	// For UnmarshalText: accepted name -> value.
+	names    map[string]string
+	foldCase bool // UnmarshalText folds the case of its input
+}
+
+// checkMethodCoverage reports constants missing from String, MarshalText and
+// UnmarshalText implementations, and names produced by String or MarshalText
+// that UnmarshalText does not accept.
+func checkMethodCoverage(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) {
+	if !ruleEnabled(CategoryMethodCoverage) && !ruleEnabled(CategoryMethodAsymmetry) {
+		return
+	}
+
+	for _, qe := range registry.LocalQuasiEnums() {
+		var encoder, decoder *methodMapping
+		for _, decl := range idx.methods[qe.Type] {
+			// Generated code is checked for staleness instead (see checkStaleStringer)
+			if idx.isGenerated(pass.Fset, decl) {
+				continue
+			}
+			switch decl.Name.Name {
+			case "String", "MarshalText":
+				mapping := encoderMapping(pass, idx, qe, decl)
+				if mapping == nil {
+					continue
+				}
+				reportUncovered(pass, qe, mapping)
+				// MarshalText is what UnmarshalText has to mirror, if present
+				if encoder == nil || decl.Name.Name == "MarshalText" {
+					encoder = mapping
+				}
+			case "UnmarshalText":
+				decoder = decoderMapping(pass, idx, qe, decl)
+				if decoder != nil {
+					reportUncovered(pass, qe, decoder)
+				}
+			}
+		}
+		if encoder != nil && decoder != nil {
+			reportAsymmetry(pass, qe, encoder, decoder)
+		}
+	}
+}
+
+// encoderMapping reads the constants handled by a String or MarshalText
+// method and the names it produces, or returns nil if the body is not a
+// switch on the receiver or a lookup keyed by it.
+func encoderMapping(pass *analysis.Pass, idx *declIndex, qe *QuasiEnumType, decl *ast.FuncDecl) *methodMapping {
+	if decl.Body == nil {
+		return nil
+	}
+	mapping := &methodMapping{decl: decl, values: make(map[string]bool), names: make(map[string]string)}
+	found := false
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SwitchStmt:
+			if node.Tag == nil || !types.Identical(pass.TypesInfo.TypeOf(node.Tag), qe.Type) {
+				return true
+			}
+			found = true
+			for _, stmt := range node.Body.List {
+				clause := stmt.(*ast.CaseClause)
+				name, hasName := returnedName(pass.TypesInfo, clause.Body)
+				for _, expr := range clause.List {
+					if value, ok := enumValue(pass.TypesInfo, qe, expr); ok {
+						mapping.values[value] = true
+						if hasName {
+							mapping.names[value] = name
+						}
+					}
+				}
+			}
+		case *ast.IndexExpr:
+			if !types.Identical(pass.TypesInfo.TypeOf(node.Index), qe.Type) {
+				return true
+			}
+			lit := lookupTable(pass.TypesInfo, idx, node.X)
+			if lit == nil {
+				return true
+			}
+			found = true
+			for _, elt := range lit.Elts {
+				kv := elt.(*ast.KeyValueExpr)
+				if value, ok := enumValue(pass.TypesInfo, qe, kv.Key); ok {
+					mapping.values[value] = true
+					if name, ok := stringValue(pass.TypesInfo, kv.Value); ok {
+						mapping.names[value] = name
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	if !found {
+		return nil
+	}
+	return mapping
+}
+
+// encoderNames returns the names produced for the constant values of a
+// quasi-enum by its MarshalText method or, failing that, its String method.
+func encoderNames(pass *analysis.Pass, idx *declIndex, qe *QuasiEnumType) map[string]string {
+	var names map[string]string
+	for _, decl := range idx.methods[qe.Type] {
+		switch decl.Name.Name {
+		case "String", "MarshalText":
+			if names != nil && decl.Name.Name == "String" {
+				continue
+			}
+			if mapping := encoderMapping(pass, idx, qe, decl); mapping != nil {
+				names = mapping.names
+			}
+		}
+	}
+	return names
+}
+
+// decoderMapping reads the constants set by an UnmarshalText method and the
+// names it accepts, or returns nil if the body is not a switch over string
+// cases or a lookup in a map of constants.
+func decoderMapping(pass *analysis.Pass, idx *declIndex, qe *QuasiEnumType, decl *ast.FuncDecl) *methodMapping {
+	if decl.Body == nil || decl.Recv == nil || len(decl.Recv.List[0].Names) == 0 {
+		return nil
+	}
+	recv := pass.TypesInfo.Defs[decl.Recv.List[0].Names[0]]
+	mapping := &methodMapping{decl: decl, values: make(map[string]bool), names: make(map[string]string)}
+	found := false
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if fn := typeutil.StaticCallee(pass.TypesInfo, node); fn != nil && fn.Pkg() != nil {
+				switch fn.Pkg().Path() + "." + fn.Name() {
+				case "strings.ToLower", "strings.ToUpper", "strings.EqualFold",
+					"bytes.ToLower", "bytes.ToUpper", "bytes.EqualFold":
+					mapping.foldCase = true
+				}
+			}
+		case *ast.SwitchStmt:
+			if node.Tag == nil || !isString(pass.TypesInfo.TypeOf(node.Tag)) {
+				return true
+			}
+			for _, stmt := range node.Body.List {
+				clause := stmt.(*ast.CaseClause)
+				value, ok := assignedValue(pass.TypesInfo, qe, recv, clause.Body)
+				if !ok {
+					continue
+				}
+				found = true
+				mapping.values[value] = true
+				for _, expr := range clause.List {
+					if name, ok := stringValue(pass.TypesInfo, expr); ok {
+						mapping.names[name] = value
+					}
+				}
+			}
+		case *ast.IndexExpr:
+			if !isString(pass.TypesInfo.TypeOf(node.Index)) {
+				return true
+			}
+			lit := lookupTable(pass.TypesInfo, idx, node.X)
+			if lit == nil {
+				return true
+			}
+			for _, elt := range lit.Elts {
+				kv := elt.(*ast.KeyValueExpr)
+				name, okName := stringValue(pass.TypesInfo, kv.Key)
+				value, okValue := enumValue(pass.TypesInfo, qe, kv.Value)
+				if okName && okValue {
+					found = true
+					mapping.values[value] = true
+					mapping.names[name] = value
+				}
+			}
+		}
+		return true
+	})
+
+	if !found {
+		return nil
+	}
+	return mapping
+}
+
+// lookupTable returns the keyed composite literal indexed by expr: either
+// the literal itself or the initializer of a package-level variable.
+// Literals with unkeyed elements (such as stringer's index tables) are ignored.
+func lookupTable(info *types.Info, idx *declIndex, expr ast.Expr) *ast.CompositeLit {
+	expr = ast.Unparen(expr)
+	if ident, ok := expr.(*ast.Ident); ok {
+		v, ok := info.Uses[ident].(*types.Var)
+		if !ok {
+			return nil
+		}
+		expr = idx.varInits[v]
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || len(lit.Elts) == 0 {
+		return nil
+	}
+	for _, elt := range lit.Elts {
+		if _, ok := elt.(*ast.KeyValueExpr); !ok {
+			return nil
+		}
+	}
+	return lit
+}
+
+// enumValue returns the exact value of expr if it is a constant of the quasi-enum type.
+func enumValue(info *types.Info, qe *QuasiEnumType, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || !types.Identical(tv.Type, qe.Type) {
+		return "", false
+	}
+	return tv.Value.ExactString(), true
+}
+
+// stringValue returns the value of a constant string expression.
+func stringValue(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// returnedName returns the name returned by a case body of the form
+// `return "name"` or `return []byte("name"), nil`.
+func returnedName(info *types.Info, body []ast.Stmt) (string, bool) {
+	if len(body) != 1 {
+		return "", false
+	}
+	ret, ok := body[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) == 0 {
+		return "", false
+	}
+	result := ret.Results[0]
+	if call, ok := result.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+			result = call.Args[0]
+		}
+	}
+	return stringValue(info, result)
+}
+
+// assignedValue returns the value of the enum constant a case body assigns
+// to the receiver (`*r = Const`).
+func assignedValue(info *types.Info, qe *QuasiEnumType, recv types.Object, body []ast.Stmt) (string, bool) {
+	for _, stmt := range body {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		star, ok := assign.Lhs[0].(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := ast.Unparen(star.X).(*ast.Ident); !ok || info.Uses[ident] != recv {
+			continue
+		}
+		if value, ok := enumValue(info, qe, assign.Rhs[0]); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// isString reports whether t has a string underlying type.
+func isString(t types.Type) bool {
+	basic, ok := t.(*types.Basic)
+	if t != nil && !ok {
+		basic, ok = t.Underlying().(*types.Basic)
+	}
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// reportUncovered reports the constants a method does not handle.
+func reportUncovered(pass *analysis.Pass, qe *QuasiEnumType, mapping *methodMapping) {
+	var missing []string
+	for _, c := range qe.Constants {
+		if !mapping.values[c.Value.ExactString()] {
+			missing = append(missing, c.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	report(pass, analysis.Diagnostic{
+		Pos:      mapping.decl.Name.Pos(),
+		Category: CategoryMethodCoverage,
+		Message: fmt.Sprintf("%s of quasi-enum type %s does not handle constants: %s",
+			mapping.decl.Name.Name, qe.Type.Obj().Name(), strings.Join(missing, ", ")),
+	})
+}
+
+// reportAsymmetry reports names produced by the encoder that the decoder
+// does not map back to the same constant. Constants the decoder does not
+// handle at all are reported by reportUncovered.
+func reportAsymmetry(pass *analysis.Pass, qe *QuasiEnumType, encoder, decoder *methodMapping) {
+	accepted := decoder.names
+	if decoder.foldCase {
+		accepted = make(map[string]string, len(decoder.names))
+		for name, value := range decoder.names {
+			accepted[strings.ToLower(name)] = value
+		}
+	}
+
+	reported := make(map[string]bool)
+	for _, c := range qe.Constants {
+		value := c.Value.ExactString()
+		name, ok := encoder.names[value]
+		if !ok || reported[value] || !decoder.values[value] {
+			continue
+		}
+		reported[value] = true
+
+		key := name
+		if decoder.foldCase {
+			key = strings.ToLower(name)
+		}
+		if accepted[key] == value {
+			continue
+		}
+		report(pass, analysis.Diagnostic{
+			Pos:      decoder.decl.Name.Pos(),
+			Category: CategoryMethodAsymmetry,
+			Message: fmt.Sprintf("UnmarshalText of quasi-enum type %s does not accept %q produced by %s for %s",
+				qe.Type.Obj().Name(), name, encoder.decl.Name.Name, c.Name),
+		})
+	}
+}
@@ -3,35 +3,29 @@ package analyzer
 
 import (
 	"go/ast"
-	"go/token"
 	"go/types"
 	"strings"
-
-	"golang.org/x/tools/go/analysis"
 )
 
 // detectByConstants implements DT-001: constants-based detection.
 // Detects types with 2+ constants in the same package.
-func detectByConstants(pass *analysis.Pass) map[*types.Named]bool {
+func detectByConstants(idx *declIndex) map[*types.Named]bool {
 	candidates := make(map[*types.Named]bool)
 
-	// Count constants per type
-	constantCounts := make(map[*types.Named]int)
-	for _, name := range pass.Pkg.Scope().Names() {
-		obj := pass.Pkg.Scope().Lookup(name)
-		if c, ok := obj.(*types.Const); ok {
-			if named, ok := c.Type().(*types.Named); ok {
-				if isBasicType(named.Underlying()) {
-					constantCounts[named]++
-				}
-			}
+	// Mark types with 2+ constants (blank constants are not declared in the package scope)
+	for named, consts := range idx.constsByType {
+		if !isBasicType(named.Underlying()) {
+			continue
 		}
-	}
 
-	// Mark types with 2+ constants
-	for typ, count := range constantCounts {
+		count := 0
+		for _, c := range consts {
+			if c.name.Name != "_" {
+				count++
+			}
+		}
 		if count >= 2 {
-			candidates[typ] = true
+			candidates[named] = true
 		}
 	}
 
@@ -40,153 +34,72 @@ func detectByConstants(pass *analysis.Pass) map[*types.Named]bool {
 
 // detectBySuffix implements DT-002: name suffix detection.
 // Detects types with name ending in "enum" (case-insensitive).
-func detectBySuffix(pass *analysis.Pass) map[*types.Named]bool {
-	candidates := make(map[*types.Named]bool)
-
-	for _, file := range pass.Files {
-		for _, decl := range file.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
-				continue
-			}
-
-			for _, spec := range genDecl.Specs {
-				typeSpec, ok := spec.(*ast.TypeSpec)
-				if !ok {
-					continue
-				}
-
-				// Check if name ends with enum keyword (case-insensitive)
-				if strings.HasSuffix(strings.ToLower(typeSpec.Name.Name), strings.ToLower(enumKeyword)) {
-					obj := pass.TypesInfo.Defs[typeSpec.Name]
-					if named, ok := obj.Type().(*types.Named); ok {
-						if isBasicType(named.Underlying()) {
-							candidates[named] = true
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return candidates
+func detectBySuffix(idx *declIndex) map[*types.Named]bool {
+	return detectTypeSpecs(idx, func(ts *typeSpecInfo) bool {
+		// Check if name ends with enum keyword (case-insensitive)
+		return strings.HasSuffix(strings.ToLower(ts.spec.Name.Name), strings.ToLower(enumKeyword))
+	})
 }
 
 // detectByInlineComment implements DT-003: inline comment detection.
 // Detects types with inline comment starting with "enum".
-func detectByInlineComment(pass *analysis.Pass) map[*types.Named]bool {
-	candidates := make(map[*types.Named]bool)
+func detectByInlineComment(idx *declIndex) map[*types.Named]bool {
+	return detectTypeSpecs(idx, func(ts *typeSpecInfo) bool {
+		// Check line comment
+		return anyCommentLine(ts.spec.Comment, startsWithEnumKeyword)
+	})
+}
 
-	for _, file := range pass.Files {
-		for _, decl := range file.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
-				continue
-			}
+// detectByPrecedingComment implements DT-004: preceding comment detection.
+// Detects types with doc comment starting with "enum".
+func detectByPrecedingComment(idx *declIndex) map[*types.Named]bool {
+	return detectTypeSpecs(idx, func(ts *typeSpecInfo) bool {
+		// Check doc comment of the enclosing declaration
+		return anyCommentLine(ts.decl.Doc, startsWithEnumKeyword)
+	})
+}
 
-			for _, spec := range genDecl.Specs {
-				typeSpec, ok := spec.(*ast.TypeSpec)
-				if !ok {
-					continue
-				}
-
-				// Check line comment
-				if typeSpec.Comment != nil {
-					for _, comment := range typeSpec.Comment.List {
-						text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
-						if startsWithEnumKeyword(text) {
-							obj := pass.TypesInfo.Defs[typeSpec.Name]
-							if named, ok := obj.Type().(*types.Named); ok {
-								if isBasicType(named.Underlying()) {
-									candidates[named] = true
-								}
-							}
-						}
-					}
-				}
-			}
+// detectByNamedComment implements DT-005: named comment detection.
+// Detects types with comment matching "TypeName enum" pattern, above the
+// declaration or, in a grouped declaration, above the spec.
+func detectByNamedComment(idx *declIndex) map[*types.Named]bool {
+	return detectTypeSpecs(idx, func(ts *typeSpecInfo) bool {
+		typeName := ts.spec.Name.Name
+		named := func(text string) bool {
+			return startsWithTypeNameEnumKeyword(text, typeName)
 		}
-	}
-
-	return candidates
+		return anyCommentLine(ts.decl.Doc, named) || anyCommentLine(ts.spec.Doc, named)
+	})
 }
 
-// detectByPrecedingComment implements DT-004: preceding comment detection.
-// Detects types with doc comment starting with "enum".
-func detectByPrecedingComment(pass *analysis.Pass) map[*types.Named]bool {
+// detectTypeSpecs returns the named types with a basic underlying type whose spec satisfies match.
+func detectTypeSpecs(idx *declIndex, match func(ts *typeSpecInfo) bool) map[*types.Named]bool {
 	candidates := make(map[*types.Named]bool)
 
-	for _, file := range pass.Files {
-		for _, decl := range file.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
-				continue
-			}
-
-			// Check doc comment
-			if genDecl.Doc != nil {
-				for _, comment := range genDecl.Doc.List {
-					text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
-					if startsWithEnumKeyword(text) {
-						for _, spec := range genDecl.Specs {
-							typeSpec, ok := spec.(*ast.TypeSpec)
-							if !ok {
-								continue
-							}
-
-							obj := pass.TypesInfo.Defs[typeSpec.Name]
-							if named, ok := obj.Type().(*types.Named); ok {
-								if isBasicType(named.Underlying()) {
-									candidates[named] = true
-								}
-							}
-						}
-					}
-				}
-			}
+	for _, ts := range idx.typeSpecs {
+		if ts.named == nil || !isBasicType(ts.named.Underlying()) {
+			continue
+		}
+		if match(ts) {
+			candidates[ts.named] = true
 		}
 	}
 
 	return candidates
 }
 
-// detectByNamedComment implements DT-005: named comment detection.
-// Detects types with comment matching "TypeName enum" pattern.
-func detectByNamedComment(pass *analysis.Pass) map[*types.Named]bool {
-	candidates := make(map[*types.Named]bool)
-
-	for _, file := range pass.Files {
-		for _, decl := range file.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
-				continue
-			}
-
-			if genDecl.Doc != nil {
-				for _, spec := range genDecl.Specs {
-					typeSpec, ok := spec.(*ast.TypeSpec)
-					if !ok {
-						continue
-					}
-
-					typeName := typeSpec.Name.Name
-					for _, comment := range genDecl.Doc.List {
-						text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
-						if startsWithTypeNameEnumKeyword(text, typeName) {
-							obj := pass.TypesInfo.Defs[typeSpec.Name]
-							if named, ok := obj.Type().(*types.Named); ok {
-								if isBasicType(named.Underlying()) {
-									candidates[named] = true
-								}
-							}
-						}
-					}
-				}
-			}
+// anyCommentLine reports whether any line of the comment group satisfies match.
+func anyCommentLine(group *ast.CommentGroup, match func(text string) bool) bool {
+	if group == nil {
+		return false
+	}
+	for _, comment := range group.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if match(text) {
+			return true
 		}
 	}
-
-	return candidates
+	return false
 }
 
 // startsWithEnumKeyword checks if text starts with the configured enum keyword (case-insensitive).
@@ -210,36 +123,36 @@ func isBasicType(t types.Type) bool {
 }
 
 // detectQuasiEnums orchestrates all detection techniques.
-func detectQuasiEnums(pass *analysis.Pass, config *DetectionConfig) map[*types.Named][]DetectionTechnique {
+func detectQuasiEnums(idx *declIndex, config *DetectionConfig) map[*types.Named][]DetectionTechnique {
 	allCandidates := make(map[*types.Named][]DetectionTechnique)
 
 	// Apply enabled detection techniques
 	if config.ConstantsDetectionEnabled {
-		for named := range detectByConstants(pass) {
+		for named := range detectByConstants(idx) {
 			allCandidates[named] = append(allCandidates[named], DT001ConstantsBased)
 		}
 	}
 
 	if config.SuffixDetectionEnabled {
-		for named := range detectBySuffix(pass) {
+		for named := range detectBySuffix(idx) {
 			allCandidates[named] = append(allCandidates[named], DT002NameSuffix)
 		}
 	}
 
 	if config.InlineCommentDetectionEnabled {
-		for named := range detectByInlineComment(pass) {
+		for named := range detectByInlineComment(idx) {
 			allCandidates[named] = append(allCandidates[named], DT003InlineComment)
 		}
 	}
 
 	if config.PrecedingCommentDetectionEnabled {
-		for named := range detectByPrecedingComment(pass) {
+		for named := range detectByPrecedingComment(idx) {
 			allCandidates[named] = append(allCandidates[named], DT004PrecedingComment)
 		}
 	}
 
 	if config.NamedCommentDetectionEnabled {
-		for named := range detectByNamedComment(pass) {
+		for named := range detectByNamedComment(idx) {
 			allCandidates[named] = append(allCandidates[named], DT005NamedComment)
 		}
 	}
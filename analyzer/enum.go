@@ -6,6 +6,7 @@ import (
 	"go/constant"
 	"go/token"
 	"go/types"
+	"sort"
 )
 
 // DetectionTechnique represents the technique used to identify a quasi-enum type.
@@ -77,12 +78,36 @@ type QuasiEnumType struct {
 	ConstBlock     *ast.GenDecl // Const block node (for constraint validation)
 	File           *ast.File    // File containing the type (for constraint validation)
 
-	// Helper method tracking (US5, US6)
-	HasStringMethod        bool // Whether type has String() string method
-	HasUnmarshalTextMethod bool // Whether type has UnmarshalText([]byte) error method
+	// Helper method tracking (US5, US6): whether the type or its pointer
+	// implements one of the -format-interfaces, -parse-interfaces and
+	// -persist-interfaces respectively
+	HasStringMethod        bool
+	HasUnmarshalTextMethod bool
+	HasPersistMethods      bool
+
+	// Value helper tracking: whether the type has an IsValid method, and a
+	// Values or All method listing its constants
+	HasIsValidMethod bool
+	HasValuesMethod  bool
 
 	// FR-047: Suggest adding enum comment when detected only by constants-based
 	SuggestEnumComment bool
+
+	// Imported is set for quasi-enums of other packages, known from their
+	// enumFact; only usage checks apply to them
+	Imported bool
+}
+
+// HasConstant reports whether obj is one of the constants of the quasi-enum.
+// Constants are compared by identity, so that a constant of the same name
+// declared elsewhere is not mistaken for a member.
+func (qe *QuasiEnumType) HasConstant(obj types.Object) bool {
+	for _, c := range qe.Constants {
+		if c.Object != nil && types.Object(c.Object) == obj {
+			return true
+		}
+	}
+	return false
 }
 
 // EnumConstant represents a valid constant value for a quasi-enum type.
@@ -94,6 +119,7 @@ type EnumConstant struct {
 	IsIota        bool
 	Expression    string
 	ConstBlock    *ast.GenDecl
+	Object        *types.Const
 }
 
 // DetectionConfig holds configuration for detection techniques.
@@ -123,16 +149,23 @@ type ConstraintConfig struct {
 	SameFileEnabled       bool
 	ExclusiveBlockEnabled bool
 	ProximityEnabled      bool
+
+	// DC-005 settings
+	ProximityLines        int  // Maximum lines between type and const block (0: no limit)
+	ProximityAllowMethods bool // Whether methods of the type may sit in between
 }
 
-// NewConstraintConfig creates a new ConstraintConfig with defaults.
+// NewConstraintConfig creates a new ConstraintConfig enabling the
+// constraints whose category passes the -only/-skip filters.
 func NewConstraintConfig() *ConstraintConfig {
 	return &ConstraintConfig{
-		MinConstantsEnabled:   !disableMinConstantsCheck,
-		SameConstBlockEnabled: !disableSameBlockCheck,
-		SameFileEnabled:       !disableSameFileCheck,
-		ExclusiveBlockEnabled: !disableExclusiveBlockCheck,
-		ProximityEnabled:      !disableProximityCheck,
+		MinConstantsEnabled:   ruleEnabled(CategoryMinConstants),
+		SameConstBlockEnabled: ruleEnabled(CategorySameConstBlock),
+		SameFileEnabled:       ruleEnabled(CategorySameFile),
+		ExclusiveBlockEnabled: ruleEnabled(CategoryExclusiveBlock),
+		ProximityEnabled:      ruleEnabled(CategoryProximity),
+		ProximityLines:        proximityLines,
+		ProximityAllowMethods: proximityAllowMethods,
 	}
 }
 
@@ -141,6 +174,7 @@ type QuasiEnumRegistry struct {
 	QuasiEnums       map[*types.Named]*QuasiEnumType
 	ConstantLookup   map[*types.Named]map[string]*EnumConstant
 	Packages         map[string][]*QuasiEnumType
+	Aliases          map[*types.Const]*EnumConstant // constants standing for enum constants by their initializer
 	DetectionConfig  *DetectionConfig
 	ConstraintConfig *ConstraintConfig
 }
@@ -151,6 +185,7 @@ func NewQuasiEnumRegistry(detectionConfig *DetectionConfig, constraintConfig *Co
 		QuasiEnums:       make(map[*types.Named]*QuasiEnumType),
 		ConstantLookup:   make(map[*types.Named]map[string]*EnumConstant),
 		Packages:         make(map[string][]*QuasiEnumType),
+		Aliases:          make(map[*types.Const]*EnumConstant),
 		DetectionConfig:  detectionConfig,
 		ConstraintConfig: constraintConfig,
 	}
@@ -172,9 +207,45 @@ func (r *QuasiEnumRegistry) RegisterQuasiEnum(qe *QuasiEnumType) {
 	r.Packages[qe.PackagePath] = append(r.Packages[qe.PackagePath], qe)
 }
 
+// ResolveConstant returns the enum constant obj stands for: the constant
+// itself if it is a member of its quasi-enum type, or the aliased constant if
+// it is a registered alias. It returns nil for other objects, even constants
+// of the type sharing a member's value.
+func (r *QuasiEnumRegistry) ResolveConstant(obj types.Object) *EnumConstant {
+	c, ok := obj.(*types.Const)
+	if !ok {
+		return nil
+	}
+	named, _ := c.Type().(*types.Named)
+	qe := r.QuasiEnums[named]
+	if qe == nil {
+		return nil
+	}
+
+	for i := range qe.Constants {
+		if qe.Constants[i].Object == c {
+			return &qe.Constants[i]
+		}
+	}
+	return r.Aliases[c]
+}
+
+// LocalQuasiEnums returns the quasi-enums declared in the analyzed package,
+// ordered by position.
+func (r *QuasiEnumRegistry) LocalQuasiEnums() []*QuasiEnumType {
+	var local []*QuasiEnumType
+	for _, qe := range r.QuasiEnums {
+		if !qe.Imported {
+			local = append(local, qe)
+		}
+	}
+	sort.Slice(local, func(i, j int) bool { return local[i].Position < local[j].Position })
+	return local
+}
+
 // IsQuasiEnumType checks if a type is a quasi-enum.
 func (r *QuasiEnumRegistry) IsQuasiEnumType(t types.Type) bool {
-	named, ok := t.(*types.Named)
+	named, ok := types.Unalias(t).(*types.Named)
 	if !ok {
 		return false
 	}
@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+)
+
+// detectAnalyzer detects the quasi-enum types of a package and exports an
+// enumFact for each of them, so that packages importing it know which of
+// their dependencies' types are quasi-enums. Analyzer builds its registry
+// from the result. It is separate from Analyzer so that the facts it
+// exports for every quasi-enum do not show up among Analyzer's facts.
+var detectAnalyzer = &analysis.Analyzer{
+	Name:       "enumdetect",
+	Doc:        "detect quasi-enum types and export them to importing packages",
+	URL:        "https://github.com/Djarvur/go-enumsafety/blob/master/docs/rules.md",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	Run:        runDetect,
+	ResultType: reflect.TypeOf((*detection)(nil)),
+	FactTypes:  []analysis.Fact{new(enumFact), new(aliasFact)},
+}
+
+// detection is the result of detectAnalyzer.
+type detection struct {
+	idx      *declIndex
+	local    []*QuasiEnumType               // quasi-enums declared in the package, by position
+	imported []*QuasiEnumType               // quasi-enums of dependencies, from their enumFacts
+	aliases  map[*types.Const]*EnumConstant // constants re-exporting imported enum constants
+}
+
+// enumFact marks a type name as a quasi-enum and lists its constants.
+type enumFact struct {
+	Constants []string // names of the constants in declaration order
+}
+
+func (*enumFact) AFact() {}
+
+func (f *enumFact) String() string {
+	return "quasi-enum " + strings.Join(f.Constants, ", ")
+}
+
+// aliasFact marks a package-level constant as an alias of an enum constant
+// of another package, such as `const Default = models.StatusActive`.
+type aliasFact struct {
+	Constant string // name of the aliased enum constant
+}
+
+func (*aliasFact) AFact() {}
+
+func (f *aliasFact) String() string {
+	return "alias of " + f.Constant
+}
+
+func runDetect(pass *analysis.Pass) (interface{}, error) {
+	det := &detection{idx: buildDeclIndex(pass)}
+
+	// Facts of dependencies only: this package's facts are exported below
+	importedAliases := make(map[*types.Const]string)
+	for _, fact := range pass.AllObjectFacts() {
+		switch f := fact.Fact.(type) {
+		case *enumFact:
+			if obj, ok := fact.Object.(*types.TypeName); ok {
+				if qe := importedQuasiEnum(obj, f); qe != nil {
+					det.imported = append(det.imported, qe)
+				}
+			}
+		case *aliasFact:
+			if c, ok := fact.Object.(*types.Const); ok {
+				importedAliases[c] = f.Constant
+			}
+		}
+	}
+	det.aliases = resolveAliases(pass, det.idx, det.imported, importedAliases)
+
+	for namedType, techniques := range detectQuasiEnums(det.idx, NewDetectionConfig()) {
+		if qe := buildQuasiEnumType(det.idx, namedType, techniques); qe != nil {
+			det.local = append(det.local, qe)
+		}
+	}
+
+	for _, list := range [][]*QuasiEnumType{det.local, det.imported} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Position < list[j].Position })
+	}
+
+	for c, member := range det.aliases {
+		if c.Pkg() == pass.Pkg {
+			pass.ExportObjectFact(c, &aliasFact{Constant: member.Name})
+		}
+	}
+	for _, qe := range det.local {
+		fact := &enumFact{}
+		for _, c := range qe.Constants {
+			fact.Constants = append(fact.Constants, c.Name)
+		}
+		pass.ExportObjectFact(qe.TypeDef, fact)
+	}
+	return det, nil
+}
+
+// resolveAliases returns the constants aliasing members of the imported
+// quasi-enums: those of dependencies, named by their aliasFacts, and the
+// package-level constants of this package whose initializer refers to a
+// member or to another alias. Constants merely sharing a member's value are
+// not aliases.
+func resolveAliases(pass *analysis.Pass, idx *declIndex, imported []*QuasiEnumType, importedAliases map[*types.Const]string) map[*types.Const]*EnumConstant {
+	aliases := make(map[*types.Const]*EnumConstant)
+	members := make(map[*types.Const]*EnumConstant)
+	byType := make(map[*types.Named]*QuasiEnumType)
+	for _, qe := range imported {
+		byType[qe.Type] = qe
+		for i := range qe.Constants {
+			members[qe.Constants[i].Object] = &qe.Constants[i]
+		}
+	}
+
+	for c, name := range importedAliases {
+		if qe := byType[asNamed(c.Type())]; qe != nil {
+			for i := range qe.Constants {
+				if qe.Constants[i].Name == name {
+					aliases[c] = &qe.Constants[i]
+				}
+			}
+		}
+	}
+
+	// Aliases may refer to aliases declared later in the package
+	for changed := true; changed; {
+		changed = false
+		for _, c := range idx.foreignConsts {
+			if aliases[c.obj] != nil || byType[asNamed(c.obj.Type())] == nil || c.index >= len(c.spec.Values) {
+				continue
+			}
+			ident := namedIdent(ast.Unparen(c.spec.Values[c.index]))
+			if ident == nil {
+				continue
+			}
+			init, _ := pass.TypesInfo.Uses[ident].(*types.Const)
+			member := members[init]
+			if member == nil {
+				member = aliases[init]
+			}
+			if member != nil && member.QuasiEnumType == asNamed(c.obj.Type()) {
+				aliases[c.obj] = member
+				changed = true
+			}
+		}
+	}
+	return aliases
+}
+
+// importedQuasiEnum rebuilds a quasi-enum of another package from its enumFact.
+// The declarations are not available, so the constraint fields stay nil.
+func importedQuasiEnum(obj *types.TypeName, fact *enumFact) *QuasiEnumType {
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+	basic, ok := named.Underlying().(*types.Basic)
+	if !ok {
+		return nil
+	}
+
+	qe := &QuasiEnumType{
+		Type:           named,
+		TypeDef:        obj,
+		UnderlyingType: basic.Kind(),
+		PackagePath:    obj.Pkg().Path(),
+		Position:       obj.Pos(),
+		Imported:       true,
+	}
+	for _, name := range fact.Constants {
+		c, ok := obj.Pkg().Scope().Lookup(name).(*types.Const)
+		if !ok {
+			continue
+		}
+		qe.Constants = append(qe.Constants, EnumConstant{
+			Name:          name,
+			Value:         c.Val(),
+			QuasiEnumType: named,
+			Position:      c.Pos(),
+			Object:        c,
+		})
+	}
+	return qe
+}
@@ -0,0 +1,263 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// This file holds the source-editing helpers shared by the suggested fixes.
+
+// fileContent returns the source of the file containing pos.
+func fileContent(pass *analysis.Pass, pos token.Pos) ([]byte, bool) {
+	tf := pass.Fset.File(pos)
+	if tf == nil {
+		return nil, false
+	}
+	content, err := pass.ReadFile(tf.Name())
+	if err != nil || len(content) != tf.Size() {
+		return nil, false
+	}
+	return content, true
+}
+
+// sourceText returns the source text between two positions of the same file.
+func sourceText(pass *analysis.Pass, from, to token.Pos) (string, bool) {
+	content, ok := fileContent(pass, from)
+	if !ok {
+		return "", false
+	}
+	tf := pass.Fset.File(from)
+	return string(content[tf.Offset(from):tf.Offset(to)]), true
+}
+
+// lineStart returns the position of the first character of the line containing pos.
+func lineStart(fset *token.FileSet, pos token.Pos) token.Pos {
+	tf := fset.File(pos)
+	return tf.LineStart(tf.Line(pos))
+}
+
+// nextLineStart returns the position just after the newline ending the line containing pos.
+func nextLineStart(fset *token.FileSet, pos token.Pos) token.Pos {
+	tf := fset.File(pos)
+	if line := tf.Line(pos); line < tf.LineCount() {
+		return tf.LineStart(line + 1)
+	}
+	return token.Pos(tf.Base() + tf.Size())
+}
+
+// deleteLines returns an edit removing the whole lines spanned by [from, to).
+func deleteLines(fset *token.FileSet, from, to token.Pos) analysis.TextEdit {
+	return analysis.TextEdit{Pos: lineStart(fset, from), End: nextLineStart(fset, to)}
+}
+
+// declRange returns the extent of a declaration including its doc comment.
+func declRange(decl *ast.GenDecl) (token.Pos, token.Pos) {
+	if decl.Doc != nil {
+		return decl.Doc.Pos(), decl.End()
+	}
+	return decl.Pos(), decl.End()
+}
+
+// specRange returns the extent of a value spec including its doc and line comments.
+func specRange(spec *ast.ValueSpec) (token.Pos, token.Pos) {
+	from, to := spec.Pos(), spec.End()
+	if spec.Doc != nil {
+		from = spec.Doc.Pos()
+	}
+	if spec.Comment != nil {
+		to = spec.Comment.End()
+	}
+	return from, to
+}
+
+// constantLiteral renders a constant value as Go source.
+func constantLiteral(v constant.Value) string {
+	switch v.Kind() {
+	case constant.String:
+		return strconv.Quote(constant.StringVal(v))
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	default:
+		return v.ExactString()
+	}
+}
+
+// usesIota reports whether the spec's values (if any) refer to iota.
+func usesIota(info *types.Info, spec *ast.ValueSpec) bool {
+	found := false
+	for _, value := range spec.Values {
+		ast.Inspect(value, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && info.Uses[ident] == types.Universe.Lookup("iota") {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// commentText renders a comment group, one comment per line, indented by indent.
+func commentText(group *ast.CommentGroup, indent string) string {
+	var sb strings.Builder
+	for _, c := range group.List {
+		sb.WriteString(indent)
+		sb.WriteString(c.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// specDoc returns the doc comment of a const spec; for an unparenthesized
+// declaration the comment is attached to the declaration.
+func specDoc(decl *ast.GenDecl, spec *ast.ValueSpec) *ast.CommentGroup {
+	if spec.Doc == nil && !decl.Lparen.IsValid() {
+		return decl.Doc
+	}
+	return spec.Doc
+}
+
+// materializedSpec renders a const spec with explicit type and values, so
+// that it keeps its meaning when moved to another position or block.
+// Doc and line comments are preserved; the result has no trailing newline.
+func materializedSpec(pass *analysis.Pass, spec *ast.ValueSpec, doc *ast.CommentGroup, indent string) string {
+	var names, values []string
+	var typ types.Type
+	for _, name := range spec.Names {
+		c, ok := pass.TypesInfo.Defs[name].(*types.Const)
+		if !ok {
+			continue
+		}
+		names = append(names, name.Name)
+		values = append(values, constantLiteral(c.Val()))
+		typ = c.Type()
+	}
+
+	var sb strings.Builder
+	if doc != nil {
+		sb.WriteString(commentText(doc, indent))
+	}
+	sb.WriteString(indent)
+	sb.WriteString(strings.Join(names, ", "))
+	if basic, ok := typ.(*types.Basic); !ok || basic.Info()&types.IsUntyped == 0 {
+		sb.WriteString(" ")
+		sb.WriteString(types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+	}
+	sb.WriteString(" = ")
+	sb.WriteString(strings.Join(values, ", "))
+	if spec.Comment != nil {
+		sb.WriteString(" ")
+		sb.WriteString(spec.Comment.List[0].Text)
+	}
+	return sb.String()
+}
+
+// movedSpec renders a const spec of decl for insertion into another const
+// block. Specs whose meaning depends on their position (implicit values or
+// iota) are materialized; others are copied verbatim. Comments are kept.
+func movedSpec(pass *analysis.Pass, decl *ast.GenDecl, spec *ast.ValueSpec, indent string) (string, bool) {
+	doc := specDoc(decl, spec)
+	if len(spec.Values) == 0 || usesIota(pass.TypesInfo, spec) {
+		return materializedSpec(pass, spec, doc, indent), true
+	}
+
+	_, to := specRange(spec)
+	text, ok := sourceText(pass, spec.Pos(), to)
+	if !ok {
+		return "", false
+	}
+	if doc != nil {
+		return commentText(doc, indent) + indent + text, true
+	}
+	return indent + text, true
+}
+
+// reindent replaces the leading whitespace of every line but the first with indent.
+func reindent(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = indent + strings.TrimLeft(lines[i], " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// insertIntoConstBlock returns the edits appending the given spec lines
+// (each already indented, without trailing newline) to a const declaration.
+// An unparenthesized declaration is rewritten as a block.
+func insertIntoConstBlock(pass *analysis.Pass, decl *ast.GenDecl, specs []string) ([]analysis.TextEdit, bool) {
+	var buf bytes.Buffer
+	if decl.Lparen.IsValid() {
+		for _, s := range specs {
+			buf.WriteString(s)
+			buf.WriteString("\n")
+		}
+		return []analysis.TextEdit{{Pos: decl.Rparen, End: decl.Rparen, NewText: buf.Bytes()}}, true
+	}
+
+	// const X T = v  ->  const (\n\tX T = v\n\t...\n)
+	spec, ok := decl.Specs[0].(*ast.ValueSpec)
+	if !ok {
+		return nil, false
+	}
+	from, to := specRange(spec)
+	text, ok := sourceText(pass, from, to)
+	if !ok {
+		return nil, false
+	}
+	buf.WriteString("(\n\t")
+	buf.WriteString(reindent(text, "\t"))
+	buf.WriteString("\n")
+	for _, s := range specs {
+		buf.WriteString(s)
+		buf.WriteString("\n")
+	}
+	buf.WriteString(")")
+	return []analysis.TextEdit{{Pos: spec.Pos(), End: to, NewText: buf.Bytes()}}, true
+}
+
+// fileAt returns the file of the package containing pos.
+func fileAt(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, file := range pass.Files {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			return file
+		}
+	}
+	return nil
+}
+
+// importEdit returns the edit adding an import of path to the file, or false
+// if the file already imports it. The import joins the last parenthesized
+// import declaration, or gets a declaration of its own.
+func importEdit(fset *token.FileSet, file *ast.File, path string) (analysis.TextEdit, bool) {
+	quoted := strconv.Quote(path)
+	var last *ast.GenDecl
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if spec.(*ast.ImportSpec).Path.Value == quoted {
+				return analysis.TextEdit{}, false
+			}
+		}
+		last = gen
+	}
+
+	switch {
+	case last != nil && last.Lparen.IsValid() && fset.Position(last.Rparen).Line > fset.Position(last.Lparen).Line:
+		at := lineStart(fset, last.Rparen)
+		return analysis.TextEdit{Pos: at, End: at, NewText: []byte("\t" + quoted + "\n")}, true
+	case last != nil:
+		return analysis.TextEdit{Pos: last.End(), End: last.End(), NewText: []byte("\nimport " + quoted)}, true
+	default:
+		return analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport " + quoted)}, true
+	}
+}
@@ -2,149 +2,653 @@ package analyzer
 
 import (
 	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
 	"go/types"
+	"go/version"
+	"path"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
+
+	"github.com/Djarvur/go-enumsafety/internal/helpersrc"
+)
+
+// Capability flags: comma-separated interfaces ("encoding.TextUnmarshaler",
+// "encoding/json.Unmarshaler", ...), any of which satisfies the capability.
+var (
+	formatInterfaces  string
+	parseInterfaces   string
+	persistInterfaces string
+)
+
+// Value helper flag: -value-helpers reports quasi-enums lacking IsValid and
+// Values methods.
+var checkValueHelpers bool
+
+// Default capability interfaces.
+const (
+	defaultFormatInterfaces = "fmt.Stringer"
+	defaultParseInterfaces  = "encoding.TextUnmarshaler,encoding/json.Unmarshaler," +
+		"gopkg.in/yaml.v3.Unmarshaler,gopkg.in/yaml.v2.Unmarshaler,encoding.BinaryUnmarshaler,flag.Value"
+)
+
+// knownInterfaces holds well-known interfaces, for packages that do not
+// import the interface's package. Types declared by that package are stubs:
+// nothing implements such an interface without importing its package.
+var knownInterfaces = map[string]*types.Interface{
+	"fmt.Stringer":                 newInterface(method("String", nil, typeString)),
+	"flag.Value":                   newInterface(method("String", nil, typeString), method("Set", tuple(typeString), typeError)),
+	"encoding.TextMarshaler":       newInterface(method("MarshalText", nil, typeBytes, typeError)),
+	"encoding.TextUnmarshaler":     newInterface(method("UnmarshalText", tuple(typeBytes), typeError)),
+	"encoding.BinaryMarshaler":     newInterface(method("MarshalBinary", nil, typeBytes, typeError)),
+	"encoding.BinaryUnmarshaler":   newInterface(method("UnmarshalBinary", tuple(typeBytes), typeError)),
+	"encoding/gob.GobEncoder":      newInterface(method("GobEncode", nil, typeBytes, typeError)),
+	"encoding/gob.GobDecoder":      newInterface(method("GobDecode", tuple(typeBytes), typeError)),
+	"encoding/json.Marshaler":      newInterface(method("MarshalJSON", nil, typeBytes, typeError)),
+	"encoding/json.Unmarshaler":    newInterface(method("UnmarshalJSON", tuple(typeBytes), typeError)),
+	"database/sql/driver.Valuer":   newInterface(method("Value", nil, stubType("database/sql/driver", "Value", typeAny), typeError)),
+	"database/sql.Scanner":         newInterface(method("Scan", tuple(typeAny), typeError)),
+	"gopkg.in/yaml.v2.Marshaler":   newInterface(method("MarshalYAML", nil, typeAny, typeError)),
+	"gopkg.in/yaml.v2.Unmarshaler": newInterface(method("UnmarshalYAML", tuple(types.NewSignatureType(nil, nil, nil, tuple(typeAny), tuple(typeError), false)), typeError)),
+	"gopkg.in/yaml.v3.Marshaler":   newInterface(method("MarshalYAML", nil, typeAny, typeError)),
+	"gopkg.in/yaml.v3.Unmarshaler": newInterface(method("UnmarshalYAML", tuple(types.NewPointer(stubType("gopkg.in/yaml.v3", "Node", types.NewStruct(nil, nil)))), typeError)),
+}
+
+// Predeclared types used by knownInterfaces.
+var (
+	typeAny    = types.Universe.Lookup("any").Type()
+	typeBool   = types.Typ[types.Bool]
+	typeBytes  = types.NewSlice(types.Typ[types.Byte])
+	typeError  = types.Universe.Lookup("error").Type()
+	typeString = types.Typ[types.String]
 )
 
+// newInterface returns the complete interface declaring the methods.
+func newInterface(methods ...*types.Func) *types.Interface {
+	return types.NewInterfaceType(methods, nil).Complete()
+}
+
+// method returns an interface method with the given parameters and results.
+func method(name string, params *types.Tuple, results ...types.Type) *types.Func {
+	sig := types.NewSignatureType(nil, nil, nil, params, tuple(results...), false)
+	return types.NewFunc(token.NoPos, nil, name, sig)
+}
+
+// tuple returns the tuple of unnamed variables of the given types.
+func tuple(typs ...types.Type) *types.Tuple {
+	vars := make([]*types.Var, len(typs))
+	for i, t := range typs {
+		vars[i] = types.NewParam(token.NoPos, nil, "", t)
+	}
+	return types.NewTuple(vars...)
+}
+
+// stubType returns a named type standing for a type of a package that is
+// not imported.
+func stubType(pkgPath, name string, underlying types.Type) *types.Named {
+	pkg := types.NewPackage(pkgPath, path.Base(pkgPath))
+	return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), underlying, nil)
+}
+
+// capabilityInterface is an interface accepted for a capability.
+type capabilityInterface struct {
+	name  string           // e.g. "encoding/json.Unmarshaler"
+	iface *types.Interface // nil if neither imported nor well known
+}
+
+// capabilities holds the interfaces accepted for each helper-method
+// capability, and the packages they are resolved against.
+type capabilities struct {
+	format   []capabilityInterface
+	parse    []capabilityInterface
+	persist  []capabilityInterface
+	imported map[string]*types.Package
+}
+
+// resolveCapabilities resolves the capability flags against the packages
+// imported, directly or indirectly, by the package under analysis.
+func resolveCapabilities(pkg *types.Package) (*capabilities, error) {
+	caps := &capabilities{imported: importedPackages(pkg)}
+	resolve := func(flagName, list string) ([]capabilityInterface, error) {
+		names := splitPatterns(list)
+		for _, name := range names {
+			if _, _, ok := splitInterfaceName(name); !ok {
+				return nil, fmt.Errorf("-%s: invalid interface %q (want import/path.Name)", flagName, name)
+			}
+		}
+		return caps.resolve(names), nil
+	}
+
+	var err error
+	if caps.format, err = resolve("format-interfaces", formatInterfaces); err != nil {
+		return nil, err
+	}
+	if caps.parse, err = resolve("parse-interfaces", parseInterfaces); err != nil {
+		return nil, err
+	}
+	if caps.persist, err = resolve("persist-interfaces", persistInterfaces); err != nil {
+		return nil, err
+	}
+	return caps, nil
+}
+
+// resolve looks up interfaces by name ("encoding/json.Unmarshaler") in the
+// imported packages, falling back to knownInterfaces.
+func (caps *capabilities) resolve(names []string) []capabilityInterface {
+	result := make([]capabilityInterface, len(names))
+	for i, name := range names {
+		result[i] = capabilityInterface{name: name, iface: knownInterfaces[name]}
+		path, typeName, _ := splitInterfaceName(name)
+		if p := caps.imported[path]; p != nil {
+			if obj, ok := p.Scope().Lookup(typeName).(*types.TypeName); ok {
+				if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+					result[i].iface = iface
+				}
+			}
+		}
+	}
+	return result
+}
+
+// splitInterfaceName splits "encoding/json.Unmarshaler" into its package path and type name.
+func splitInterfaceName(name string) (string, string, bool) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.LastIndex(name[slash+1:], ".")
+	if dot <= 0 || slash+1+dot == len(name)-1 {
+		return "", "", false
+	}
+	return name[:slash+1+dot], name[slash+2+dot:], true
+}
+
+// importedPackages returns the packages imported by pkg, directly or indirectly, by path.
+func importedPackages(pkg *types.Package) map[string]*types.Package {
+	imported := make(map[string]*types.Package)
+	var visit func(p *types.Package)
+	visit = func(p *types.Package) {
+		for _, imp := range p.Imports() {
+			if imported[imp.Path()] == nil {
+				imported[imp.Path()] = imp
+				visit(imp)
+			}
+		}
+	}
+	visit(pkg)
+	return imported
+}
+
+// implementsAny reports whether T or *T implements one of the interfaces.
+// Unknown interfaces cannot be implemented.
+func implementsAny(t types.Type, interfaces []capabilityInterface) bool {
+	ptr := types.NewPointer(t)
+	for _, ci := range interfaces {
+		if ci.iface != nil && (types.Implements(t, ci.iface) || types.Implements(ptr, ci.iface)) {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceNames returns the names of the interfaces.
+func interfaceNames(interfaces []capabilityInterface) []string {
+	names := make([]string, len(interfaces))
+	for i, ci := range interfaces {
+		names[i] = ci.name
+	}
+	return names
+}
+
 // checkStringMethod warns if a quasi-enum type lacks a String() method (US5).
-func checkStringMethod(pass *analysis.Pass, registry *QuasiEnumRegistry) {
-	if disableStringMethodCheck {
+func checkStringMethod(pass *analysis.Pass, registry *QuasiEnumRegistry, caps *capabilities) {
+	if !ruleEnabled(CategoryStringMethod) {
 		return
 	}
 
-	for _, qe := range registry.QuasiEnums {
+	for _, qe := range registry.LocalQuasiEnums() {
 		if !qe.HasStringMethod {
-			warnMissingStringMethod(pass, qe)
+			warnMissingStringMethod(pass, qe, caps)
 		}
 	}
 }
 
 // checkUnmarshalTextMethod warns if a quasi-enum type lacks an UnmarshalText() method (US6).
-func checkUnmarshalTextMethod(pass *analysis.Pass, registry *QuasiEnumRegistry) {
-	if disableUnmarshalMethodCheck {
+func checkUnmarshalTextMethod(pass *analysis.Pass, registry *QuasiEnumRegistry, caps *capabilities) {
+	if !ruleEnabled(CategoryUnmarshalMethod) {
 		return
 	}
 
-	for _, qe := range registry.QuasiEnums {
+	for _, qe := range registry.LocalQuasiEnums() {
 		if !qe.HasUnmarshalTextMethod {
-			warnMissingUnmarshalTextMethod(pass, qe)
+			warnMissingUnmarshalTextMethod(pass, qe, caps)
+		}
+	}
+}
+
+// checkPersistMethods warns if a quasi-enum type implements none of the
+// -persist-interfaces. The check is off while the list is empty.
+func checkPersistMethods(pass *analysis.Pass, registry *QuasiEnumRegistry, caps *capabilities) {
+	if len(caps.persist) == 0 {
+		return
+	}
+
+	names := interfaceNames(caps.persist)
+	for _, qe := range registry.LocalQuasiEnums() {
+		if qe.HasPersistMethods {
+			continue
 		}
+		report(pass, analysis.Diagnostic{
+			Pos:      qe.Position,
+			Category: CategoryPersistMethod,
+			Message: fmt.Sprintf("quasi-enum type %s lacks persistence methods; implement one of: %s",
+				qe.Type.Obj().Name(), strings.Join(names, ", ")),
+		})
 	}
 }
 
-// warnMissingStringMethod reports a warning for missing String() method.
-func warnMissingStringMethod(pass *analysis.Pass, qe *QuasiEnumType) {
+// warnMissingStringMethod reports a warning for missing String() method,
+// or for none of the -format-interfaces if they are not the default.
+func warnMissingStringMethod(pass *analysis.Pass, qe *QuasiEnumType, caps *capabilities) {
 	typeName := qe.Type.Obj().Name()
 
 	msg := fmt.Sprintf(
 		"quasi-enum type %s lacks a String() method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it",
 		typeName,
 	)
+	if formatInterfaces != defaultFormatInterfaces {
+		msg = fmt.Sprintf("quasi-enum type %s lacks formatting methods; implement one of: %s",
+			typeName, strings.Join(interfaceNames(caps.format), ", "))
+	}
 
-	pass.Reportf(qe.Position, "%s", msg)
+	report(pass, analysis.Diagnostic{
+		Pos:      qe.Position,
+		Category: CategoryStringMethod,
+		Message:  msg,
+	})
 }
 
-// warnMissingUnmarshalTextMethod reports a warning for missing UnmarshalText() method.
-func warnMissingUnmarshalTextMethod(pass *analysis.Pass, qe *QuasiEnumType) {
+// warnMissingUnmarshalTextMethod reports a warning for missing UnmarshalText()
+// method, or for none of the -parse-interfaces if they are not the default.
+func warnMissingUnmarshalTextMethod(pass *analysis.Pass, qe *QuasiEnumType, caps *capabilities) {
 	typeName := qe.Type.Obj().Name()
 
 	msg := fmt.Sprintf(
 		"quasi-enum type %s lacks an UnmarshalText([]byte) error method; consider using github.com/Djarvur/go-silly-enum to generate it",
 		typeName,
 	)
+	if parseInterfaces != defaultParseInterfaces {
+		msg = fmt.Sprintf("quasi-enum type %s lacks parsing methods; implement one of: %s",
+			typeName, strings.Join(interfaceNames(caps.parse), ", "))
+	}
 
-	pass.Reportf(qe.Position, "%s", msg)
+	report(pass, analysis.Diagnostic{
+		Pos:      qe.Position,
+		Category: CategoryUnmarshalMethod,
+		Message:  msg,
+	})
 }
 
-// hasMethod checks if a named type has a method with the given name and signature.
-func hasMethod(namedType *types.Named, methodName string, signature string) bool {
-	// Get the method set for the type
-	methodSet := types.NewMethodSet(namedType)
+// detectHelperMethods updates the QuasiEnumType with the helper capabilities
+// its type (or pointer) implements.
+func detectHelperMethods(caps *capabilities, qe *QuasiEnumType) {
+	qe.HasStringMethod = implementsAny(qe.Type, caps.format)
+	qe.HasUnmarshalTextMethod = implementsAny(qe.Type, caps.parse)
+	qe.HasPersistMethods = implementsAny(qe.Type, caps.persist)
+	qe.HasIsValidMethod = implementsAny(qe.Type, []capabilityInterface{{"IsValid", isValidInterface}})
+	qe.HasValuesMethod = implementsAny(qe.Type, valuesInterfaces(qe.Type))
+}
 
-	// Look for the method
-	for i := 0; i < methodSet.Len(); i++ {
-		method := methodSet.At(i)
-		if method.Obj().Name() == methodName {
-			// For String(), check signature: func() string
-			if methodName == "String" {
-				sig, ok := method.Type().(*types.Signature)
-				if !ok {
-					continue
+// isValidInterface is implemented by quasi-enums validating their values.
+var isValidInterface = newInterface(method("IsValid", nil, typeBool))
+
+// valuesInterfaces returns the interfaces of the methods listing the values
+// of a quasi-enum: Values() []T and, if its package imports iter,
+// All() iter.Seq[T].
+func valuesInterfaces(named *types.Named) []capabilityInterface {
+	interfaces := []capabilityInterface{{"Values", newInterface(method("Values", nil, types.NewSlice(named)))}}
+	for _, imp := range named.Obj().Pkg().Imports() {
+		if imp.Path() != "iter" {
+			continue
+		}
+		if seq, ok := imp.Scope().Lookup("Seq").(*types.TypeName); ok {
+			if all, err := types.Instantiate(nil, seq.Type(), []types.Type{named}, false); err == nil {
+				interfaces = append(interfaces, capabilityInterface{"All", newInterface(method("All", nil, all))})
+			}
+		}
+	}
+	return interfaces
+}
+
+// checkValueHelperMethods reports quasi-enum types lacking an IsValid method
+// or a Values/All accessor, with a fix generating them after the const block.
+// The check runs only with -value-helpers.
+func checkValueHelperMethods(pass *analysis.Pass, registry *QuasiEnumRegistry) {
+	if !checkValueHelpers {
+		return
+	}
+
+	for _, qe := range registry.LocalQuasiEnums() {
+		if qe.HasIsValidMethod && qe.HasValuesMethod {
+			continue
+		}
+		typeName := qe.Type.Obj().Name()
+
+		// All needs the iter package of Go 1.23
+		var file *ast.File
+		if qe.ConstBlock != nil {
+			file = fileAt(pass, qe.ConstBlock.Pos())
+		}
+		withIter := file != nil && supportsIter(pass, file)
+
+		var missing []string
+		if !qe.HasIsValidMethod {
+			missing = append(missing, "IsValid() bool")
+		}
+		if !qe.HasValuesMethod {
+			missing = append(missing, fmt.Sprintf("Values() []%s", typeName))
+			if withIter {
+				missing = append(missing, fmt.Sprintf("All() iter.Seq[%s]", typeName))
+			}
+		}
+
+		diagnostic := analysis.Diagnostic{
+			Pos:      qe.Position,
+			Category: CategoryValueHelpers,
+			Message: fmt.Sprintf("quasi-enum type %s lacks %s; generate them to validate and list its values",
+				typeName, strings.Join(missing, ", ")),
+		}
+		if file != nil {
+			edits := []analysis.TextEdit{{
+				Pos:     qe.ConstBlock.End(),
+				End:     qe.ConstBlock.End(),
+				NewText: []byte(valueHelpersSource(qe, withIter)),
+			}}
+			if withIter && !qe.HasValuesMethod {
+				if edit, ok := importEdit(pass.Fset, file, "iter"); ok {
+					edits = append(edits, edit)
 				}
-				// No parameters, one string result
-				if sig.Params().Len() == 0 && sig.Results().Len() == 1 {
-					if basic, ok := sig.Results().At(0).Type().(*types.Basic); ok {
-						if basic.Kind() == types.String {
-							return true
-						}
-					}
+			}
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Generate value helpers for %s", typeName),
+				TextEdits: edits,
+			}}
+		}
+		report(pass, diagnostic)
+	}
+}
+
+// supportsIter reports whether the file is compiled with Go 1.23 or later.
+// Files of unknown version are assumed to be recent.
+func supportsIter(pass *analysis.Pass, file *ast.File) bool {
+	v := pass.TypesInfo.FileVersions[file]
+	if v == "" {
+		v = pass.Pkg.GoVersion()
+	}
+	return v == "" || version.Compare(v, "go1.23") >= 0
+}
+
+// valueHelpersSource renders the missing IsValid, Values and All methods.
+// Constants sharing a value are listed once, by their first name.
+func valueHelpersSource(qe *QuasiEnumType, withIter bool) string {
+	typeName := qe.Type.Obj().Name()
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, c := range qe.Constants {
+		if value := c.Value.ExactString(); !seen[value] {
+			seen[value] = true
+			names = append(names, c.Name)
+		}
+	}
+	all := make([]string, len(qe.Constants))
+	for i, c := range qe.Constants {
+		all[i] = c.Name
+	}
+	recv := helpersrc.ReceiverName(typeName, all)
+
+	var sb strings.Builder
+	if !qe.HasIsValidMethod {
+		sb.WriteString("\n\n" + helpersrc.IsValid(typeName, recv, names))
+	}
+	if !qe.HasValuesMethod {
+		sb.WriteString("\n\n" + helpersrc.Values(typeName, names))
+		if withIter {
+			fmt.Fprintf(&sb, "\n\n// All returns an iterator over the %s constants in declaration order.\n", typeName)
+			fmt.Fprintf(&sb, "func (%s %s) All() iter.Seq[%s] {\n", recv, typeName, typeName)
+			fmt.Fprintf(&sb, "\treturn func(yield func(%s) bool) {\n", typeName)
+			fmt.Fprintf(&sb, "\t\tfor _, v := range %s.Values() {\n\t\t\tif !yield(v) {\n\t\t\t\treturn\n\t\t\t}\n\t\t}\n\t}\n}", recv)
+		}
+	}
+	return sb.String()
+}
+
+// checkStaleValueHelpers reports IsValid methods rejecting constants of
+// their quasi-enum, and Values or All methods listing the constants in a
+// literal that omits some of them.
+func checkStaleValueHelpers(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) {
+	if !ruleEnabled(CategoryStaleValueHelpers) {
+		return
+	}
+
+	for _, qe := range registry.LocalQuasiEnums() {
+		for _, decl := range idx.methods[qe.Type] {
+			var accepted func(EnumConstant) bool
+			verb := "omits"
+			switch decl.Name.Name {
+			case "IsValid":
+				accepted = validityCheck(pass.TypesInfo, qe, decl)
+				verb = "rejects"
+			case "Values", "All":
+				accepted = listedConstants(pass.TypesInfo, idx, qe, decl)
+			}
+			if accepted == nil {
+				continue
+			}
+
+			var missing []string
+			for _, c := range qe.Constants {
+				if !accepted(c) {
+					missing = append(missing, c.Name)
 				}
 			}
+			if len(missing) == 0 {
+				continue
+			}
+			report(pass, analysis.Diagnostic{
+				Pos:      decl.Name.Pos(),
+				Category: CategoryStaleValueHelpers,
+				Message: fmt.Sprintf("%s of quasi-enum type %s %s constants: %s; update or regenerate it",
+					decl.Name.Name, qe.Type.Obj().Name(), verb, strings.Join(missing, ", ")),
+			})
+		}
+	}
+}
+
+// validityCheck reads the constants accepted by an IsValid method whose body
+// is either a switch on the receiver, with clauses returning true or false
+// and followed by `return false` unless it has a default clause, or a single
+// returned comparison of the receiver with constants
+// (`s >= First && s <= Last`). It returns nil if the method has another form.
+func validityCheck(info *types.Info, qe *QuasiEnumType, decl *ast.FuncDecl) func(EnumConstant) bool {
+	if decl.Body == nil || decl.Recv == nil || len(decl.Recv.List[0].Names) == 0 {
+		return nil
+	}
+	recv := info.Defs[decl.Recv.List[0].Names[0]]
 
-			// For UnmarshalText, check signature: func([]byte) error
-			if methodName == "UnmarshalText" {
-				sig, ok := method.Type().(*types.Signature)
+	body := decl.Body.List
+	if len(body) == 0 || len(body) > 2 || len(body) == 2 && !returnsFalse(info, body[1:]) {
+		return nil
+	}
+	switch stmt := body[0].(type) {
+	case *ast.SwitchStmt:
+		return switchValidity(info, qe, recv, stmt, len(body) == 2)
+	case *ast.ReturnStmt:
+		if len(body) != 1 || len(stmt.Results) != 1 {
+			return nil
+		}
+		for _, c := range qe.Constants {
+			if _, ok := evalValidity(info, recv, stmt.Results[0], c.Value); !ok {
+				return nil
+			}
+		}
+		return func(c EnumConstant) bool {
+			result, _ := evalValidity(info, recv, stmt.Results[0], c.Value)
+			return result
+		}
+	}
+	return nil
+}
+
+// switchValidity reads the constants accepted by a switch on the receiver
+// whose clauses all return a constant boolean. Without a following
+// `return false`, the switch must end with a default clause returning false.
+func switchValidity(info *types.Info, qe *QuasiEnumType, recv types.Object, stmt *ast.SwitchStmt, returnsAfter bool) func(EnumConstant) bool {
+	if tag, ok := ast.Unparen(stmt.Tag).(*ast.Ident); stmt.Init != nil || !ok || info.Uses[tag] != recv {
+		return nil
+	}
+	values := make(map[string]bool)
+	for _, s := range stmt.Body.List {
+		clause := s.(*ast.CaseClause)
+		accept, ok := returnedBool(info, clause.Body)
+		switch {
+		case !ok || clause.List == nil && accept:
+			return nil
+		case clause.List == nil:
+			returnsAfter = true
+		case accept:
+			for _, expr := range clause.List {
+				value, ok := enumValue(info, qe, expr)
 				if !ok {
-					continue
-				}
-				// One []byte parameter, one error result
-				if sig.Params().Len() == 1 && sig.Results().Len() == 1 {
-					// Check parameter is []byte
-					if slice, ok := sig.Params().At(0).Type().(*types.Slice); ok {
-						if basic, ok := slice.Elem().(*types.Basic); ok {
-							if basic.Kind() == types.Byte {
-								// Check result is error
-								if named, ok := sig.Results().At(0).Type().(*types.Named); ok {
-									if named.Obj().Name() == "error" {
-										return true
-									}
-								}
-							}
-						}
-					}
+					return nil
 				}
+				values[value] = true
 			}
 		}
 	}
+	if !returnsAfter {
+		return nil
+	}
+	return func(c EnumConstant) bool { return values[c.Value.ExactString()] }
+}
 
-	return false
+// returnsFalse reports whether a body is `return false`.
+func returnsFalse(info *types.Info, body []ast.Stmt) bool {
+	result, ok := returnedBool(info, body)
+	return ok && !result
 }
 
-// detectHelperMethods updates the QuasiEnumType with helper method presence.
-func detectHelperMethods(namedType *types.Named, qe *QuasiEnumType) {
-	qe.HasStringMethod = hasMethod(namedType, "String", "func() string")
-
-	// UnmarshalText is typically defined on pointer receiver, so check both
-	qe.HasUnmarshalTextMethod = hasMethod(namedType, "UnmarshalText", "func([]byte) error")
-	if !qe.HasUnmarshalTextMethod {
-		// Check pointer receiver
-		ptrType := types.NewPointer(namedType)
-		methodSet := types.NewMethodSet(ptrType)
-		for i := 0; i < methodSet.Len(); i++ {
-			method := methodSet.At(i)
-			if method.Obj().Name() == "UnmarshalText" {
-				sig, ok := method.Type().(*types.Signature)
-				if !ok {
-					continue
+// returnedBool returns the value of a body that is `return true` or
+// `return false`.
+func returnedBool(info *types.Info, body []ast.Stmt) (bool, bool) {
+	if len(body) != 1 {
+		return false, false
+	}
+	ret, ok := body[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false, false
+	}
+	tv, ok := info.Types[ret.Results[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(tv.Value), true
+}
+
+// evalValidity evaluates a boolean expression over comparisons of the
+// receiver with constants, for the receiver holding value.
+func evalValidity(info *types.Info, recv types.Object, expr ast.Expr, value constant.Value) (bool, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		if e.Op != token.NOT {
+			return false, false
+		}
+		result, ok := evalValidity(info, recv, e.X, value)
+		return !result, ok
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND, token.LOR:
+			x, okX := evalValidity(info, recv, e.X, value)
+			y, okY := evalValidity(info, recv, e.Y, value)
+			if e.Op == token.LAND {
+				return x && y, okX && okY
+			}
+			return x || y, okX && okY
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			x, okX := operandValue(info, recv, e.X, value)
+			y, okY := operandValue(info, recv, e.Y, value)
+			if !okX || !okY {
+				return false, false
+			}
+			return constant.Compare(x, e.Op, y), true
+		}
+	}
+	return false, false
+}
+
+// operandValue returns the value of a comparison operand: the receiver, a
+// conversion of it, or a constant.
+func operandValue(info *types.Info, recv types.Object, expr ast.Expr, value constant.Value) (constant.Value, bool) {
+	expr = ast.Unparen(expr)
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		return tv.Value, true
+	}
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+			expr = ast.Unparen(call.Args[0])
+		}
+	}
+	if ident, ok := expr.(*ast.Ident); ok && info.Uses[ident] == recv {
+		return value, true
+	}
+	return nil, false
+}
+
+// listedConstants reads the constants listed by a Values or All method: the
+// elements of slice or array literals of the quasi-enum in its body, or in
+// the initializers of the package-level variables it refers to. It returns
+// nil if there is no such literal.
+func listedConstants(info *types.Info, idx *declIndex, qe *QuasiEnumType, decl *ast.FuncDecl) func(EnumConstant) bool {
+	if decl.Body == nil {
+		return nil
+	}
+
+	values := make(map[string]bool)
+	found := false
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Ident:
+			if v, ok := info.Uses[node].(*types.Var); ok && idx.varInits[v] != nil && v.Parent() == v.Pkg().Scope() {
+				ast.Inspect(idx.varInits[v], visit)
+			}
+		case *ast.CompositeLit:
+			var elem types.Type
+			switch t := info.TypeOf(node).Underlying().(type) {
+			case *types.Slice:
+				elem = t.Elem()
+			case *types.Array:
+				elem = t.Elem()
+			}
+			if elem == nil || !types.Identical(elem, qe.Type) {
+				return true
+			}
+			found = true
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
 				}
-				// One []byte parameter, one error result
-				if sig.Params().Len() == 1 && sig.Results().Len() == 1 {
-					// Check parameter is []byte
-					if slice, ok := sig.Params().At(0).Type().(*types.Slice); ok {
-						if basic, ok := slice.Elem().(*types.Basic); ok {
-							if basic.Kind() == types.Byte {
-								// Check result is error (interface type)
-								resultType := sig.Results().At(0).Type()
-								if resultType.String() == "error" {
-									qe.HasUnmarshalTextMethod = true
-									break
-								}
-							}
-						}
-					}
+				if value, ok := enumValue(info, qe, elt); ok {
+					values[value] = true
 				}
 			}
+			return false
 		}
+		return true
+	}
+	ast.Inspect(decl.Body, visit)
+
+	if !found {
+		return nil
 	}
+	return func(c EnumConstant) bool { return values[c.Value.ExactString()] }
 }
@@ -0,0 +1,240 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// declIndex holds the package-level declarations and usage candidates of a
+// package. It is built by a single inspector traversal and shared by
+// detection, registry construction and usage checking, so that no step
+// has to walk pass.Files again.
+type declIndex struct {
+	// Package-level type specs in source order.
+	typeSpecs []*typeSpecInfo
+	// Package-level type specs by declared type name.
+	typesByName map[*types.TypeName]*typeSpecInfo
+	// Package-level constants grouped by their named type, in source order.
+	constsByType map[*types.Named][]*constSpecInfo
+	// Package-level constants of other packages' named types, in source order.
+	foreignConsts []*constSpecInfo
+	// Constants of named types declared in functions, in source order.
+	localConsts []*constSpecInfo
+	// Nodes checked for usage violations (US1-US3), in source order.
+	usageNodes []ast.Node
+	// Struct types, checked for persisted quasi-enum fields.
+	structTypes []*ast.StructType
+	// Method declarations by receiver base type.
+	methods map[*types.Named][]*ast.FuncDecl
+	// Function and method declarations with a body, in source order.
+	funcDecls []*ast.FuncDecl
+	// Initializers of package-level variables.
+	varInits map[*types.Var]ast.Expr
+	// Package-level variables with an initializer, in source order.
+	vars []*types.Var
+	// Index expressions, checked against arrays indexed by quasi-enums.
+	indexExprs []*ast.IndexExpr
+	// Files marked as generated (see ast.IsGenerated).
+	generated map[*token.File]bool
+	// The `func _()` index assertions of stringer-generated files.
+	stringerAssertions []*ast.FuncDecl
+}
+
+// typeSpecInfo records a package-level type spec with its enclosing declaration.
+type typeSpecInfo struct {
+	spec  *ast.TypeSpec
+	decl  *ast.GenDecl
+	file  *ast.File
+	named *types.Named // nil for aliases and other non-named types
+}
+
+// constSpecInfo records one name of a const spec with its enclosing declaration.
+type constSpecInfo struct {
+	name  *ast.Ident
+	index int // index of name in spec.Names
+	spec  *ast.ValueSpec
+	decl  *ast.GenDecl
+	file  *ast.File
+	obj   *types.Const
+}
+
+// indexNodes are the node types visited by buildDeclIndex.
+var indexNodes = []ast.Node{
+	(*ast.File)(nil),
+	(*ast.FuncDecl)(nil),
+	(*ast.GenDecl)(nil),
+	(*ast.TypeSpec)(nil),
+	(*ast.ValueSpec)(nil),
+	(*ast.AssignStmt)(nil),
+	(*ast.CallExpr)(nil),
+	(*ast.CompositeLit)(nil),
+	(*ast.StructType)(nil),
+	(*ast.IndexExpr)(nil),
+}
+
+// buildDeclIndex indexes the package in one inspector.WithStack traversal.
+func buildDeclIndex(pass *analysis.Pass) *declIndex {
+	idx := &declIndex{
+		typesByName:  make(map[*types.TypeName]*typeSpecInfo),
+		constsByType: make(map[*types.Named][]*constSpecInfo),
+		methods:      make(map[*types.Named][]*ast.FuncDecl),
+		varInits:     make(map[*types.Var]ast.Expr),
+		generated:    make(map[*token.File]bool),
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.WithStack(indexNodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch node := n.(type) {
+		case *ast.File:
+			if ast.IsGenerated(node) {
+				idx.generated[pass.Fset.File(node.Package)] = true
+			}
+		case *ast.GenDecl:
+			if node.Tok == token.VAR {
+				idx.usageNodes = append(idx.usageNodes, node)
+			}
+		case *ast.TypeSpec:
+			// Package-level specs sit directly below File and GenDecl
+			if len(stack) == 3 {
+				idx.addTypeSpec(pass, node, stack[1].(*ast.GenDecl), stack[0].(*ast.File))
+			}
+		case *ast.FuncDecl:
+			idx.addMethod(pass, node)
+			if node.Body != nil {
+				idx.funcDecls = append(idx.funcDecls, node)
+			}
+			if node.Name.Name == "_" && node.Recv == nil && isStringerFile(stack[0].(*ast.File)) {
+				idx.stringerAssertions = append(idx.stringerAssertions, node)
+			}
+		case *ast.ValueSpec:
+			if len(stack) == 3 {
+				switch decl := stack[1].(*ast.GenDecl); decl.Tok {
+				case token.CONST:
+					idx.addConstSpec(pass, node, decl, stack[0].(*ast.File))
+				case token.VAR:
+					idx.addVarSpec(pass, node)
+				}
+			} else if decl := stack[len(stack)-2].(*ast.GenDecl); decl.Tok == token.CONST {
+				idx.addLocalConstSpec(pass, node, decl, stack[0].(*ast.File))
+			}
+		case *ast.AssignStmt, *ast.CallExpr, *ast.CompositeLit:
+			idx.usageNodes = append(idx.usageNodes, node)
+		case *ast.StructType:
+			idx.structTypes = append(idx.structTypes, node)
+		case *ast.IndexExpr:
+			idx.indexExprs = append(idx.indexExprs, node)
+		}
+		return true
+	})
+
+	return idx
+}
+
+// addTypeSpec records a package-level type spec.
+func (idx *declIndex) addTypeSpec(pass *analysis.Pass, spec *ast.TypeSpec, decl *ast.GenDecl, file *ast.File) {
+	obj, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+	if !ok {
+		return
+	}
+
+	info := &typeSpecInfo{spec: spec, decl: decl, file: file}
+	info.named, _ = obj.Type().(*types.Named)
+
+	idx.typeSpecs = append(idx.typeSpecs, info)
+	idx.typesByName[obj] = info
+}
+
+// addConstSpec records the names of a package-level const spec of a named type.
+func (idx *declIndex) addConstSpec(pass *analysis.Pass, spec *ast.ValueSpec, decl *ast.GenDecl, file *ast.File) {
+	for i, name := range spec.Names {
+		obj, ok := pass.TypesInfo.Defs[name].(*types.Const)
+		if !ok {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		info := &constSpecInfo{
+			name:  name,
+			index: i,
+			spec:  spec,
+			decl:  decl,
+			file:  file,
+			obj:   obj,
+		}
+		// Constants of other packages' types are foreign constants or aliases, not members
+		if named.Obj().Pkg() != pass.Pkg {
+			idx.foreignConsts = append(idx.foreignConsts, info)
+			continue
+		}
+		idx.constsByType[named] = append(idx.constsByType[named], info)
+	}
+}
+
+// addLocalConstSpec records the names of a const spec of a named type
+// declared in a function.
+func (idx *declIndex) addLocalConstSpec(pass *analysis.Pass, spec *ast.ValueSpec, decl *ast.GenDecl, file *ast.File) {
+	for i, name := range spec.Names {
+		obj, ok := pass.TypesInfo.Defs[name].(*types.Const)
+		if !ok {
+			continue
+		}
+		if _, ok := obj.Type().(*types.Named); ok {
+			idx.localConsts = append(idx.localConsts, &constSpecInfo{
+				name:  name,
+				index: i,
+				spec:  spec,
+				decl:  decl,
+				file:  file,
+				obj:   obj,
+			})
+		}
+	}
+}
+
+// addMethod records a method declaration under its receiver base type.
+func (idx *declIndex) addMethod(pass *analysis.Pass, decl *ast.FuncDecl) {
+	if decl.Recv == nil {
+		return
+	}
+	fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	recv := fn.Type().(*types.Signature).Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	if named, ok := recv.(*types.Named); ok {
+		idx.methods[named] = append(idx.methods[named], decl)
+	}
+}
+
+// addVarSpec records the initializers of a package-level var spec.
+func (idx *declIndex) addVarSpec(pass *analysis.Pass, spec *ast.ValueSpec) {
+	if len(spec.Values) != len(spec.Names) {
+		return
+	}
+	for i, name := range spec.Names {
+		if v, ok := pass.TypesInfo.Defs[name].(*types.Var); ok {
+			idx.varInits[v] = spec.Values[i]
+			idx.vars = append(idx.vars, v)
+		}
+	}
+}
+
+// isGenerated reports whether the node belongs to a generated file.
+func (idx *declIndex) isGenerated(fset *token.FileSet, node ast.Node) bool {
+	return idx.generated[fset.File(node.Pos())]
+}
@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkStructLayout reports package-level struct types with quasi-enum fields
+// whose size, for the target platform, shrinks once the enums take the types
+// suggested by checkUint8Optimization and the fields are ordered by
+// decreasing alignment.
+func checkStructLayout(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex, compact map[*types.Named]types.BasicKind) {
+	if !ruleEnabled(CategoryStructLayout) {
+		return
+	}
+
+	sizes := typesSizes(pass)
+	for _, info := range idx.typeSpecs {
+		st, ok := info.spec.Type.(*ast.StructType)
+		if !ok || info.named == nil || info.spec.TypeParams != nil {
+			continue
+		}
+		checkStructType(pass, registry, idx, info, st, sizes, compact)
+	}
+}
+
+// layoutField is a field declaration of a struct with the types of its names.
+type layoutField struct {
+	field *ast.Field
+	vars  []*types.Var
+	align int64
+}
+
+// checkStructType reports one struct type if its optimal layout is smaller.
+func checkStructType(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex, info *typeSpecInfo, st *ast.StructType, sizes types.Sizes, compact map[*types.Named]types.BasicKind) {
+	typ, ok := info.named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	// Group the struct's fields by declaration, substituting the compact
+	// type for quasi-enum fields
+	var (
+		fields  []layoutField
+		hasEnum bool
+		shrunk  []*types.Named
+		next    int
+	)
+	for _, field := range st.Fields.List {
+		names := max(len(field.Names), 1)
+		lf := layoutField{field: field}
+		for i := next; i < next+names; i++ {
+			v := typ.Field(i)
+			t := v.Type()
+			if !isSized(t) {
+				return
+			}
+			if named := asNamed(t); named != nil && registry.IsQuasiEnumType(named) {
+				hasEnum = true
+				if kind, ok := compact[named]; ok {
+					t = types.Typ[kind]
+					if !slices.Contains(shrunk, named) {
+						shrunk = append(shrunk, named)
+					}
+				}
+			}
+			lf.vars = append(lf.vars, types.NewField(v.Pos(), v.Pkg(), v.Name(), t, v.Embedded()))
+		}
+		lf.align = sizes.Alignof(lf.vars[0].Type())
+		fields = append(fields, lf)
+		next += names
+	}
+	if !hasEnum {
+		return
+	}
+
+	reordered := slices.Clone(fields)
+	slices.SortStableFunc(reordered, func(a, b layoutField) int {
+		return int(b.align - a.align)
+	})
+
+	current := sizes.Sizeof(typ)
+	optimal := sizes.Sizeof(structOf(reordered))
+	if optimal >= current {
+		return
+	}
+
+	moved := !slices.EqualFunc(fields, reordered, func(a, b layoutField) bool { return a.field == b.field })
+	var changes []string
+	for _, named := range shrunk {
+		changes = append(changes, fmt.Sprintf("%s as %s", named.Obj().Name(), types.Typ[compact[named]].Name()))
+	}
+	if moved {
+		changes = append(changes, "its fields reordered")
+	}
+
+	typeName := info.named.Obj().Name()
+	diagnostic := analysis.Diagnostic{
+		Pos:      info.spec.Name.Pos(),
+		Category: CategoryStructLayout,
+		Message: fmt.Sprintf("struct %s takes %d bytes but could take %d with %s",
+			typeName, current, optimal, strings.Join(changes, " and ")),
+	}
+	if fix := layoutFix(pass, registry, idx, info, st, reordered, moved, shrunk, compact); fix != nil {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{*fix}
+	}
+	report(pass, diagnostic)
+}
+
+// layoutFix returns the fix making all the changes of a struct layout
+// diagnostic: the shrunk quasi-enums take their compact types and the fields
+// are reordered if moved. No fix is offered when one of the changes cannot
+// be made, since the others alone would not reach the reported size.
+func layoutFix(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex, info *typeSpecInfo, st *ast.StructType, order []layoutField, moved bool, shrunk []*types.Named, compact map[*types.Named]types.BasicKind) *analysis.SuggestedFix {
+	var (
+		edits   []analysis.TextEdit
+		changes []string
+	)
+	for _, named := range shrunk {
+		edit, ok := compactTypeEdit(registry.QuasiEnums[named], compact[named])
+		if !ok {
+			return nil
+		}
+		edits = append(edits, edit)
+		changes = append(changes, fmt.Sprintf("change %s base type to %s", named.Obj().Name(), types.Typ[compact[named]].Name()))
+	}
+	if moved {
+		edit, ok := reorderFieldsEdit(pass, idx, info, st, order)
+		if !ok {
+			return nil
+		}
+		edits = append(edits, edit)
+		changes = append(changes, fmt.Sprintf("reorder fields of %s", info.named.Obj().Name()))
+	}
+
+	message := strings.Join(changes, " and ")
+	return &analysis.SuggestedFix{
+		Message:   strings.ToUpper(message[:1]) + message[1:],
+		TextEdits: edits,
+	}
+}
+
+// isSized reports whether the size of t is known. Non-generic package-level
+// structs cannot refer to type parameters, so only invalid types lack one.
+func isSized(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return !ok || basic.Kind() != types.Invalid
+}
+
+// structOf returns the struct type declaring the fields in the given order.
+func structOf(fields []layoutField) *types.Struct {
+	var vars []*types.Var
+	for _, f := range fields {
+		vars = append(vars, f.vars...)
+	}
+	return types.NewStruct(vars, nil)
+}
+
+// reorderFieldsEdit returns the edit rewriting the field list in the given
+// order. Field declarations are moved with their doc and line comments. No
+// fix is offered when fields share a line, when comments attached to no
+// field sit between them, or when the package builds the struct from an
+// unkeyed composite literal, which reordering would break.
+func reorderFieldsEdit(pass *analysis.Pass, idx *declIndex, info *typeSpecInfo, st *ast.StructType, order []layoutField) (analysis.TextEdit, bool) {
+	list := st.Fields.List
+	if hasUnkeyedLiteral(pass, idx, info.named) {
+		return analysis.TextEdit{}, false
+	}
+
+	texts := make(map[*ast.Field]string, len(list))
+	prevLine := pass.Fset.Position(st.Fields.Opening).Line
+	for _, field := range list {
+		from, to := fieldRange(field)
+		if pass.Fset.Position(from).Line <= prevLine {
+			return analysis.TextEdit{}, false
+		}
+		prevLine = pass.Fset.Position(to).Line
+		text, ok := sourceText(pass, from, to)
+		if !ok {
+			return analysis.TextEdit{}, false
+		}
+		texts[field] = text
+	}
+	if pass.Fset.Position(st.Fields.Closing).Line <= prevLine {
+		return analysis.TextEdit{}, false
+	}
+
+	from, _ := fieldRange(list[0])
+	_, to := fieldRange(list[len(list)-1])
+	if hasFloatingFieldComments(info.file, st, from, to) {
+		return analysis.TextEdit{}, false
+	}
+	indent, ok := sourceText(pass, lineStart(pass.Fset, from), from)
+	if !ok || strings.TrimSpace(indent) != "" {
+		return analysis.TextEdit{}, false
+	}
+
+	var sb strings.Builder
+	for i, f := range order {
+		if i > 0 {
+			sb.WriteString("\n" + indent)
+		}
+		sb.WriteString(texts[f.field])
+	}
+
+	return analysis.TextEdit{Pos: from, End: to, NewText: []byte(sb.String())}, true
+}
+
+// fieldRange returns the range of a field declaration with its comments.
+func fieldRange(field *ast.Field) (token.Pos, token.Pos) {
+	from, to := field.Pos(), field.End()
+	if field.Doc != nil {
+		from = field.Doc.Pos()
+	}
+	if field.Comment != nil {
+		to = field.Comment.End()
+	}
+	return from, to
+}
+
+// hasFloatingFieldComments reports whether the fields of a struct type in
+// [from, to) are interleaved with comments that are not the doc or line
+// comment of one of them.
+func hasFloatingFieldComments(file *ast.File, st *ast.StructType, from, to token.Pos) bool {
+	attached := make(map[*ast.CommentGroup]bool)
+	for _, field := range st.Fields.List {
+		attached[field.Doc] = true
+		attached[field.Comment] = true
+	}
+	for _, group := range file.Comments {
+		if group.Pos() >= from && group.End() <= to && !attached[group] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUnkeyedLiteral reports whether the package has a composite literal of
+// the named type listing its fields by position.
+func hasUnkeyedLiteral(pass *analysis.Pass, idx *declIndex, named *types.Named) bool {
+	for _, n := range idx.usageNodes {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || len(lit.Elts) == 0 {
+			continue
+		}
+		if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+			continue
+		}
+		if t := pass.TypesInfo.TypeOf(lit); t != nil && types.Identical(t, named) {
+			return true
+		}
+	}
+	return false
+}
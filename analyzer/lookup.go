@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkLookupTables checks package-level arrays, slices and maps keyed by
+// quasi-enum constants, such as `[...]string{StatusActive: "active"}`: they
+// must have an entry for every constant, and arrays must be long enough for
+// every constant value. Index expressions `arr[s]` with a non-constant
+// quasi-enum index are checked against the length of the array.
+func checkLookupTables(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) {
+	if !ruleEnabled(CategoryLookupTable) {
+		return
+	}
+
+	for _, v := range idx.vars {
+		init := idx.varInits[v]
+		if idx.isGenerated(pass.Fset, init) {
+			continue
+		}
+		// Copies such as `var b = a` are reported at the table they copy
+		lit := lookupTable(pass.TypesInfo, idx, init)
+		if lit == nil || lit != ast.Unparen(init) {
+			continue
+		}
+		qe, keys := tableKeys(pass.TypesInfo, registry, lit)
+		if qe == nil {
+			continue
+		}
+
+		var missing []string
+		for _, c := range qe.Constants {
+			if !keys[c.Value.ExactString()] {
+				missing = append(missing, c.Name)
+			}
+		}
+		if len(missing) > 0 {
+			report(pass, analysis.Diagnostic{
+				Pos:      lit.Pos(),
+				Category: CategoryLookupTable,
+				Message: fmt.Sprintf("lookup table %s lacks constants of quasi-enum type %s: %s",
+					v.Name(), qe.Type.Obj().Name(), strings.Join(missing, ", ")),
+			})
+		}
+
+		if arr, ok := v.Type().Underlying().(*types.Array); ok {
+			if c := outOfRange(qe, arr.Len()); c != nil {
+				report(pass, analysis.Diagnostic{
+					Pos:      lit.Pos(),
+					Category: CategoryLookupTable,
+					Message: fmt.Sprintf("lookup table %s has length %d; constant %s of quasi-enum type %s (value %s) is out of range",
+						v.Name(), arr.Len(), c.Name, qe.Type.Obj().Name(), c.Value.ExactString()),
+				})
+			}
+		}
+	}
+
+	// Generated index tables are checked by QOL-stale-stringer
+	for _, ix := range idx.indexExprs {
+		if !idx.isGenerated(pass.Fset, ix) {
+			checkEnumIndex(pass, registry, ix)
+		}
+	}
+}
+
+// tableKeys returns the quasi-enum keying a composite literal and the values
+// of its keys. Every key must be a constant of the quasi-enum type; for maps,
+// the key type must be the quasi-enum.
+func tableKeys(info *types.Info, registry *QuasiEnumRegistry, lit *ast.CompositeLit) (*QuasiEnumType, map[string]bool) {
+	var qe *QuasiEnumType
+	switch t := info.TypeOf(lit).Underlying().(type) {
+	case *types.Map:
+		qe = registry.QuasiEnums[asNamed(t.Key())]
+	case *types.Array, *types.Slice:
+		qe = registry.QuasiEnums[asNamed(info.TypeOf(lit.Elts[0].(*ast.KeyValueExpr).Key))]
+	}
+	if qe == nil {
+		return nil, nil
+	}
+
+	keys := make(map[string]bool, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		value, ok := enumValue(info, qe, elt.(*ast.KeyValueExpr).Key)
+		if !ok {
+			return nil, nil
+		}
+		keys[value] = true
+	}
+	return qe, keys
+}
+
+// checkEnumIndex reports an index expression whose quasi-enum index may
+// exceed the array it indexes.
+func checkEnumIndex(pass *analysis.Pass, registry *QuasiEnumRegistry, ix *ast.IndexExpr) {
+	tv, ok := pass.TypesInfo.Types[ix.Index]
+	if !ok || tv.Value != nil {
+		return
+	}
+	qe := registry.QuasiEnums[asNamed(tv.Type)]
+	if qe == nil {
+		return
+	}
+
+	t := pass.TypesInfo.TypeOf(ix.X)
+	if t == nil {
+		return
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	arr, ok := t.Underlying().(*types.Array)
+	if !ok {
+		return
+	}
+
+	if c := outOfRange(qe, arr.Len()); c != nil {
+		report(pass, analysis.Diagnostic{
+			Pos:      ix.Index.Pos(),
+			Category: CategoryLookupTable,
+			Message: fmt.Sprintf("index of quasi-enum type %s into array of length %d; constant %s (value %s) is out of range",
+				qe.Type.Obj().Name(), arr.Len(), c.Name, c.Value.ExactString()),
+		})
+	}
+}
+
+// outOfRange returns the first constant of the quasi-enum that is not a
+// valid index of an array of the given length.
+func outOfRange(qe *QuasiEnumType, length int64) *EnumConstant {
+	for i := range qe.Constants {
+		c := &qe.Constants[i]
+		v, exact := constant.Int64Val(constant.ToInt(c.Value))
+		if !exact || v < 0 || v >= length {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkEnumMarker implements FR-047: quasi-enums detected only by their
+// constants (DT-001) get an informational suggestion to add a named enum
+// comment (DT-005), so that they stay detected if DT-001 is disabled.
+func checkEnumMarker(pass *analysis.Pass, registry *QuasiEnumRegistry) {
+	for _, qe := range registry.LocalQuasiEnums() {
+		if !qe.SuggestEnumComment || qe.TypeDecl == nil {
+			continue
+		}
+
+		typeName := qe.Type.Obj().Name()
+		marker := fmt.Sprintf("// %s %s", typeName, enumKeyword)
+
+		// The marker goes on its own line right above the type keyword or,
+		// in a grouped declaration, above the spec (with its indentation),
+		// extending the doc comment if there is one
+		at := lineStart(pass.Fset, qe.TypeDecl.Pos())
+		if qe.TypeDecl.Lparen.IsValid() {
+			spec := typeSpecOf(qe)
+			if spec == nil {
+				continue
+			}
+			at = lineStart(pass.Fset, spec.Pos())
+			indent, ok := sourceText(pass, at, spec.Pos())
+			if !ok {
+				continue
+			}
+			marker = indent + marker
+		}
+		report(pass, analysis.Diagnostic{
+			Pos:      qe.Position,
+			Category: CategoryEnumMarker,
+			Message: fmt.Sprintf("quasi-enum type %s is detected only by its constants; add a \"// %s %s\" comment to mark it explicitly",
+				typeName, typeName, enumKeyword),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Add \"// %s %s\" comment", typeName, enumKeyword),
+				TextEdits: []analysis.TextEdit{{Pos: at, End: at, NewText: []byte(marker + "\n")}},
+			}},
+		})
+	}
+}
+
+// typeSpecOf returns the spec declaring the quasi-enum type in its declaration.
+func typeSpecOf(qe *QuasiEnumType) *ast.TypeSpec {
+	for _, spec := range qe.TypeDecl.Specs {
+		if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Pos() == qe.Position {
+			return ts
+		}
+	}
+	return nil
+}
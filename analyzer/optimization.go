@@ -2,123 +2,159 @@ package analyzer
 
 import (
 	"fmt"
-	"go/ast"
+	"go/constant"
 	"go/types"
+	"math"
 
 	"golang.org/x/tools/go/analysis"
 )
 
-// checkUint8Optimization suggests using uint8 for enums with <256 constants using larger types (US4).
-func checkUint8Optimization(pass *analysis.Pass, registry *QuasiEnumRegistry) {
-	if disableUint8Suggestion {
-		return
+// compactKinds lists the candidate underlying types in order of preference:
+// smallest first, unsigned before signed of the same size.
+var compactKinds = []types.BasicKind{
+	types.Uint8, types.Int8,
+	types.Uint16, types.Int16,
+	types.Uint32, types.Int32,
+}
+
+// checkUint8Optimization suggests the smallest integer type holding all constant values
+// for enums using larger types (US4). It returns the suggested type of each
+// enum, for the struct layout check.
+func checkUint8Optimization(pass *analysis.Pass, registry *QuasiEnumRegistry) map[*types.Named]types.BasicKind {
+	if !ruleEnabled(CategoryCompactType) {
+		return nil
 	}
 
-	for _, qe := range registry.QuasiEnums {
-		// Check if already using uint8
-		if qe.UnderlyingType == types.Uint8 {
+	sizes := typesSizes(pass)
+	suggested := make(map[*types.Named]types.BasicKind)
+	for _, qe := range registry.LocalQuasiEnums() {
+		if len(qe.Constants) == 0 || !isIntegerKind(qe.UnderlyingType) {
 			continue
 		}
 
-		// Count constants
-		constCount := len(qe.Constants)
-		if constCount >= 256 {
+		kind, ok := smallestFittingKind(qe.Constants)
+		if !ok {
 			continue
 		}
 
-		// Check if using a larger integer type
-		if !isLargerIntegerType(qe.UnderlyingType) {
+		// Suggest only a strictly smaller type: int8 enums with
+		// non-negative values gain nothing from uint8
+		if sizes.Sizeof(types.Typ[kind]) >= sizes.Sizeof(types.Typ[qe.UnderlyingType]) {
 			continue
 		}
 
-		// Suggest uint8 with autofix
-		suggestUint8(pass, qe)
+		suggested[qe.Type] = kind
+		suggestCompactType(pass, qe, kind)
+	}
+	return suggested
+}
+
+// typesSizes returns the sizes of the target platform.
+func typesSizes(pass *analysis.Pass) types.Sizes {
+	if pass.TypesSizes != nil {
+		return pass.TypesSizes
 	}
+	return types.SizesFor("gc", "amd64")
 }
 
-// isLargerIntegerType checks if the type is larger than uint8.
-func isLargerIntegerType(kind types.BasicKind) bool {
+// isIntegerKind checks if the kind is a sized or platform-dependent integer.
+func isIntegerKind(kind types.BasicKind) bool {
 	switch kind {
-	case types.Int, types.Int16, types.Int32, types.Int64,
-		types.Uint, types.Uint16, types.Uint32, types.Uint64:
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
 		return true
 	default:
 		return false
 	}
 }
 
-// suggestUint8 creates a suggestion to use uint8 with autofix capability.
-func suggestUint8(pass *analysis.Pass, qe *QuasiEnumType) {
-	typeName := qe.Type.Obj().Name()
+// smallestFittingKind returns the first of compactKinds that can represent
+// the value of every constant.
+func smallestFittingKind(constants []EnumConstant) (types.BasicKind, bool) {
+	for _, kind := range compactKinds {
+		fits := true
+		for _, c := range constants {
+			if c.Value == nil || !fitsKind(c.Value, kind) {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return kind, true
+		}
+	}
+	return types.Invalid, false
+}
+
+// fitsKind reports whether the integer constant is representable in the kind.
+func fitsKind(v constant.Value, kind types.BasicKind) bool {
+	v = constant.ToInt(v)
+	if v.Kind() != constant.Int {
+		return false
+	}
+	i, exact := constant.Int64Val(v)
+	if !exact {
+		return false
+	}
 
-	// Get the string representation of the underlying type
-	var currentType string
-	switch qe.UnderlyingType {
-	case types.Int:
-		currentType = "int"
+	switch kind {
+	case types.Uint8:
+		return i >= 0 && i <= math.MaxUint8
 	case types.Int8:
-		currentType = "int8"
-	case types.Int16:
-		currentType = "int16"
-	case types.Int32:
-		currentType = "int32"
-	case types.Int64:
-		currentType = "int64"
-	case types.Uint:
-		currentType = "uint"
+		return i >= math.MinInt8 && i <= math.MaxInt8
 	case types.Uint16:
-		currentType = "uint16"
+		return i >= 0 && i <= math.MaxUint16
+	case types.Int16:
+		return i >= math.MinInt16 && i <= math.MaxInt16
 	case types.Uint32:
-		currentType = "uint32"
-	case types.Uint64:
-		currentType = "uint64"
+		return i >= 0 && i <= math.MaxUint32
+	case types.Int32:
+		return i >= math.MinInt32 && i <= math.MaxInt32
 	default:
-		currentType = "unknown"
+		return false
 	}
+}
+
+// suggestCompactType creates a suggestion to use the smaller type with autofix capability.
+func suggestCompactType(pass *analysis.Pass, qe *QuasiEnumType, kind types.BasicKind) {
+	typeName := qe.Type.Obj().Name()
+	kindName := types.Typ[kind].Name()
 
 	msg := fmt.Sprintf(
-		"quasi-enum type %s uses %s but has only %d constants; consider using uint8 for memory optimization",
+		"quasi-enum type %s uses %s but all values fit in %s; consider using it for memory optimization",
 		typeName,
-		currentType,
-		len(qe.Constants),
+		types.Typ[qe.UnderlyingType].Name(),
+		kindName,
 	)
 
 	// Create diagnostic with suggested fix
 	diagnostic := analysis.Diagnostic{
 		Pos:      qe.Position,
 		Message:  msg,
-		Category: "optimization",
-		SuggestedFixes: []analysis.SuggestedFix{
-			{
-				Message: fmt.Sprintf("Change %s base type to uint8", typeName),
-				TextEdits: []analysis.TextEdit{
-					{
-						Pos:     qe.TypeDecl.Pos(),
-						End:     qe.TypeDecl.End(),
-						NewText: []byte(generateUint8TypeDecl(qe)),
-					},
-				},
-			},
-		},
+		Category: CategoryCompactType,
 	}
 
-	pass.Report(diagnostic)
-}
+	if edit, ok := compactTypeEdit(qe, kind); ok {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Change %s base type to %s", typeName, kindName),
+			TextEdits: []analysis.TextEdit{edit},
+		}}
+	}
 
-// generateUint8TypeDecl generates the type declaration with uint8 base type.
-func generateUint8TypeDecl(qe *QuasiEnumType) string {
-	typeName := qe.Type.Obj().Name()
+	report(pass, diagnostic)
+}
 
-	// Find the type spec within the GenDecl
-	for _, spec := range qe.TypeDecl.Specs {
-		if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-			if typeSpec.Name.Name == typeName {
-				// Generate: type TypeName uint8
-				return fmt.Sprintf("type %s uint8", typeName)
-			}
-		}
+// compactTypeEdit returns the edit changing the underlying type of a
+// quasi-enum to kind. Types declared inside functions have no package-level
+// declaration to rewrite. Only the type expression of the spec is replaced,
+// so sibling specs of a grouped declaration and comments are kept.
+func compactTypeEdit(qe *QuasiEnumType, kind types.BasicKind) (analysis.TextEdit, bool) {
+	if qe.TypeDecl == nil {
+		return analysis.TextEdit{}, false
 	}
-
-	// Fallback
-	return fmt.Sprintf("type %s uint8", typeName)
+	spec := typeSpecOf(qe)
+	if spec == nil {
+		return analysis.TextEdit{}, false
+	}
+	return analysis.TextEdit{Pos: spec.Type.Pos(), End: spec.Type.End(), NewText: []byte(types.Typ[kind].Name())}, true
 }
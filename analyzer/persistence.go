@@ -0,0 +1,277 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// persistenceFormat describes how a serialization format stores a value
+// other than as its underlying number.
+type persistenceFormat struct {
+	tag      string   // struct tag key selecting the format, if any
+	encoders []string // interfaces, any of which makes the value encodable
+	decoders []string // interfaces, any of which makes the value decodable
+}
+
+var (
+	formatJSON = &persistenceFormat{
+		tag:      "json",
+		encoders: []string{"encoding/json.Marshaler", "encoding.TextMarshaler"},
+		decoders: []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"},
+	}
+	formatYAML = &persistenceFormat{
+		tag:      "yaml",
+		encoders: []string{"gopkg.in/yaml.v3.Marshaler", "gopkg.in/yaml.v2.Marshaler", "encoding.TextMarshaler"},
+		decoders: []string{"gopkg.in/yaml.v3.Unmarshaler", "gopkg.in/yaml.v2.Unmarshaler", "encoding.TextUnmarshaler"},
+	}
+	formatSQL = &persistenceFormat{
+		tag:      "db",
+		encoders: []string{"database/sql/driver.Valuer"},
+		decoders: []string{"database/sql.Scanner"},
+	}
+	formatGob = &persistenceFormat{
+		encoders: []string{"encoding/gob.GobEncoder", "encoding.BinaryMarshaler", "encoding.TextMarshaler"},
+		decoders: []string{"encoding/gob.GobDecoder", "encoding.BinaryUnmarshaler", "encoding.TextUnmarshaler"},
+	}
+)
+
+// taggedFormats are the formats selected by struct tags.
+var taggedFormats = []*persistenceFormat{formatJSON, formatYAML, formatSQL}
+
+// persistenceCall describes a function or method persisting its arguments.
+type persistenceCall struct {
+	format *persistenceFormat
+	encode bool // true if the arguments are encoded, false if decoded into
+	arg    int  // index of the persisted argument, or -1 for all arguments
+}
+
+// persistenceCalls maps qualified function names, as rendered by
+// types.Func.FullName, to the persistence they perform.
+var persistenceCalls = map[string]persistenceCall{
+	"encoding/json.Marshal":           {formatJSON, true, 0},
+	"encoding/json.MarshalIndent":     {formatJSON, true, 0},
+	"encoding/json.Unmarshal":         {formatJSON, false, 1},
+	"(*encoding/json.Encoder).Encode": {formatJSON, true, 0},
+	"(*encoding/json.Decoder).Decode": {formatJSON, false, 0},
+	"(*encoding/gob.Encoder).Encode":  {formatGob, true, 0},
+	"(*encoding/gob.Decoder).Decode":  {formatGob, false, 0},
+	"(*database/sql.Rows).Scan":       {formatSQL, false, -1},
+	"(*database/sql.Row).Scan":        {formatSQL, false, -1},
+}
+
+// checkPersistence warns when quasi-enum values are persisted as their
+// underlying numbers: struct fields carrying json, yaml or db tags, and values
+// passed to encoding/json, encoding/gob or database/sql Scan, whose type lacks
+// the methods the format uses to encode or decode it.
+func checkPersistence(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex, caps *capabilities) {
+	if !ruleEnabled(CategoryPersistence) {
+		return
+	}
+
+	for _, st := range idx.structTypes {
+		for _, field := range st.Fields.List {
+			checkTaggedField(pass, registry, caps, field)
+		}
+	}
+
+	for _, node := range idx.usageNodes {
+		if call, ok := node.(*ast.CallExpr); ok {
+			checkPersistenceCall(pass, registry, caps, call)
+		}
+	}
+}
+
+// checkTaggedField reports a quasi-enum field whose tags select a format it cannot be stored in.
+func checkTaggedField(pass *analysis.Pass, registry *QuasiEnumRegistry, caps *capabilities, field *ast.Field) {
+	if field.Tag == nil {
+		return
+	}
+	qe := enumOf(registry, pass.TypesInfo.TypeOf(field.Type))
+	if qe == nil {
+		return
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+
+	for _, format := range taggedFormats {
+		if !taggedFor(reflect.StructTag(tag), format.tag) {
+			continue
+		}
+		missing := missingMethods(caps, qe.Type, format, true, true)
+		if len(missing) == 0 {
+			continue
+		}
+		via := fmt.Sprintf("%s tag", format.tag)
+		if len(field.Names) > 0 {
+			via = fmt.Sprintf("%s tag of field %s", format.tag, field.Names[0].Name)
+		}
+		reportPersistence(pass, field.Pos(), qe, via, true, missing)
+	}
+}
+
+// checkPersistenceCall reports quasi-enum values reaching a persisting call.
+func checkPersistenceCall(pass *analysis.Pass, registry *QuasiEnumRegistry, caps *capabilities, call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return
+	}
+	pc, ok := persistenceCalls[fn.FullName()]
+	if !ok {
+		return
+	}
+	// json.Marshal rather than encoding/json.Marshal
+	via := strings.Replace(fn.FullName(), fn.Pkg().Path(), fn.Pkg().Name(), 1)
+
+	args := call.Args
+	if pc.arg >= 0 {
+		if pc.arg >= len(args) {
+			return
+		}
+		args = args[pc.arg : pc.arg+1]
+	}
+	for _, arg := range args {
+		reported := make(map[*QuasiEnumType]bool)
+		visitPersistedEnums(registry, pass.TypesInfo.TypeOf(arg), pc.format, make(map[types.Type]bool), func(qe *QuasiEnumType) {
+			if reported[qe] {
+				return
+			}
+			reported[qe] = true
+			if missing := missingMethods(caps, qe.Type, pc.format, pc.encode, !pc.encode); len(missing) > 0 {
+				reportPersistence(pass, arg.Pos(), qe, via, pc.encode, missing)
+			}
+		})
+	}
+}
+
+// visitPersistedEnums calls visit for every quasi-enum stored when a value
+// of type t is persisted in format. Struct fields tagged for the format are
+// skipped, as they are reported at the field.
+func visitPersistedEnums(registry *QuasiEnumRegistry, t types.Type, format *persistenceFormat, seen map[types.Type]bool, visit func(*QuasiEnumType)) {
+	if t == nil || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	if qe := registry.QuasiEnums[asNamed(t)]; qe != nil {
+		visit(qe)
+		return
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		visitPersistedEnums(registry, u.Elem(), format, seen, visit)
+	case *types.Slice:
+		visitPersistedEnums(registry, u.Elem(), format, seen, visit)
+	case *types.Array:
+		visitPersistedEnums(registry, u.Elem(), format, seen, visit)
+	case *types.Map:
+		visitPersistedEnums(registry, u.Elem(), format, seen, visit)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !field.Exported() && !field.Embedded() {
+				continue
+			}
+			tag := reflect.StructTag(u.Tag(i))
+			if format.tag != "" && (taggedFor(tag, format.tag) || tag.Get(format.tag) == "-") {
+				continue
+			}
+			visitPersistedEnums(registry, field.Type(), format, seen, visit)
+		}
+	}
+}
+
+// enumOf returns the quasi-enum stored by a field of type t, looking through
+// pointers, slices, arrays and map values.
+func enumOf(registry *QuasiEnumRegistry, t types.Type) *QuasiEnumType {
+	for t != nil {
+		if qe := registry.QuasiEnums[asNamed(t)]; qe != nil {
+			return qe
+		}
+		switch u := t.Underlying().(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// asNamed returns t as a named type, or nil.
+func asNamed(t types.Type) *types.Named {
+	named, _ := types.Unalias(t).(*types.Named)
+	return named
+}
+
+// taggedFor reports whether a struct tag stores the field under key (not "-").
+func taggedFor(tag reflect.StructTag, key string) bool {
+	value, ok := tag.Lookup(key)
+	if !ok {
+		return false
+	}
+	name, _, _ := strings.Cut(value, ",")
+	return name != "-" || strings.Contains(value, ",")
+}
+
+// missingMethods lists the methods of the interfaces of format that the type
+// (or its pointer) implements none of, for encoding and/or decoding, e.g.
+// "MarshalJSON/MarshalText".
+func missingMethods(caps *capabilities, named *types.Named, format *persistenceFormat, encode, decode bool) []string {
+	var missing []string
+	if encode {
+		if encoders := caps.resolve(format.encoders); !implementsAny(named, encoders) {
+			missing = append(missing, methodNames(encoders))
+		}
+	}
+	if decode {
+		if decoders := caps.resolve(format.decoders); !implementsAny(named, decoders) {
+			missing = append(missing, methodNames(decoders))
+		}
+	}
+	return missing
+}
+
+// methodNames joins the distinct method names of interfaces with "/".
+func methodNames(interfaces []capabilityInterface) string {
+	var names []string
+	for _, ci := range interfaces {
+		for i := 0; ci.iface != nil && i < ci.iface.NumMethods(); i++ {
+			if name := ci.iface.Method(i).Name(); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// reportPersistence reports a quasi-enum persisted, or loaded if encode is
+// false, without the methods of its format.
+func reportPersistence(pass *analysis.Pass, pos token.Pos, qe *QuasiEnumType, via string, encode bool, missing []string) {
+	format := "quasi-enum type %s persisted via %s lacks %s; it is stored as its underlying value"
+	if !encode {
+		format = "quasi-enum type %s loaded via %s lacks %s; it is read from its underlying value"
+	}
+	report(pass, analysis.Diagnostic{
+		Pos:      pos,
+		Category: CategoryPersistence,
+		Message:  fmt.Sprintf(format, qe.Type.Obj().Name(), via, strings.Join(missing, " and ")),
+	})
+}
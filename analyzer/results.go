@@ -0,0 +1,503 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// unvalidatedResultFact marks a function whose quasi-enum results may hold
+// values outside the enum's constants, because it converts to the quasi-enum
+// type (or returns the result of such a function) without comparing the
+// converted value first.
+type unvalidatedResultFact struct {
+	Results []int    // indices of the unvalidated results
+	Types   []string // names of the quasi-enum types of those results
+}
+
+func (*unvalidatedResultFact) AFact() {}
+
+func (f *unvalidatedResultFact) String() string {
+	parts := make([]string, len(f.Results))
+	for i, result := range f.Results {
+		parts[i] = fmt.Sprintf("%d:%s", result, f.Types[i])
+	}
+	return "unvalidated results " + strings.Join(parts, ", ")
+}
+
+// typeOf returns the quasi-enum type name of the given result, or "".
+func (f *unvalidatedResultFact) typeOf(result int) string {
+	for i, r := range f.Results {
+		if r == result {
+			return f.Types[i]
+		}
+	}
+	return ""
+}
+
+// exportUnvalidatedResults exports an unvalidatedResultFact for every
+// function of the package returning quasi-enum values it has not validated.
+// Functions returning the results of such functions are marked as well.
+func exportUnvalidatedResults(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) {
+	local := make(map[*types.Func]*unvalidatedResultFact)
+	lookup := func(fn *types.Func) *unvalidatedResultFact {
+		if fact := local[fn]; fact != nil {
+			return fact
+		}
+		return importUnvalidatedResult(pass, fn)
+	}
+
+	// Propagate through same-package wrappers until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range idx.funcDecls {
+			fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok || local[fn] != nil {
+				continue
+			}
+			if fact := unvalidatedResults(pass, registry, decl, fn, lookup); fact != nil {
+				local[fn] = fact
+				changed = true
+			}
+		}
+	}
+
+	for fn, fact := range local {
+		pass.ExportObjectFact(fn, fact)
+	}
+}
+
+// importUnvalidatedResult returns the unvalidatedResultFact of fn, or nil.
+func importUnvalidatedResult(pass *analysis.Pass, fn *types.Func) *unvalidatedResultFact {
+	fact := new(unvalidatedResultFact)
+	if pass.ImportObjectFact == nil || !pass.ImportObjectFact(fn, fact) {
+		return nil
+	}
+	return fact
+}
+
+// unvalidatedResults returns the fact for a function declaration, or nil if
+// all its quasi-enum results are validated.
+func unvalidatedResults(pass *analysis.Pass, registry *QuasiEnumRegistry, decl *ast.FuncDecl, fn *types.Func,
+	lookup func(*types.Func) *unvalidatedResultFact) *unvalidatedResultFact {
+	results := fn.Type().(*types.Signature).Results()
+
+	enums := make(map[int]*QuasiEnumType)
+	for i := 0; i < results.Len(); i++ {
+		if qe := registry.QuasiEnums[asNamed(results.At(i).Type())]; qe != nil {
+			enums[i] = qe
+		}
+	}
+	if len(enums) == 0 {
+		return nil
+	}
+
+	unvalidated := make(map[int]bool)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			switch {
+			case len(node.Results) == results.Len():
+				for i, expr := range node.Results {
+					if enums[i] != nil && !unvalidated[i] && isUnvalidatedValue(pass, decl.Body, expr, 0, lookup, nil) {
+						unvalidated[i] = true
+					}
+				}
+			case len(node.Results) == 1:
+				// return f(), forwarding all the results of f
+				for i := range enums {
+					if !unvalidated[i] && isUnvalidatedValue(pass, decl.Body, node.Results[0], i, lookup, nil) {
+						unvalidated[i] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	if len(unvalidated) == 0 {
+		return nil
+	}
+
+	fact := new(unvalidatedResultFact)
+	for i := 0; i < results.Len(); i++ {
+		if unvalidated[i] {
+			fact.Results = append(fact.Results, i)
+			fact.Types = append(fact.Types, enums[i].Type.Obj().Name())
+		}
+	}
+	return fact
+}
+
+// isUnvalidatedValue reports whether the given result of a returned
+// expression is a conversion of a value not compared before the return, or
+// an unvalidated result of a call. Local variables are followed to their
+// assignments, unless they are compared before the return; seen holds the
+// variables being followed.
+func isUnvalidatedValue(pass *analysis.Pass, body *ast.BlockStmt, expr ast.Expr, result int,
+	lookup func(*types.Func) *unvalidatedResultFact, seen map[*types.Var]bool) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		v, ok := pass.TypesInfo.Uses[e].(*types.Var)
+		if !ok || seen[v] || v.Pos() < body.Lbrace || v.Pos() > body.Rbrace {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[*types.Var]bool)
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		for _, a := range assignmentsOf(pass, body, v, e.Pos()) {
+			if !isCompared(pass, body, map[*types.Var]bool{v: true}, a.pos, e.Pos()) &&
+				isUnvalidatedValue(pass, body, a.expr, a.result, lookup, seen) {
+				return true
+			}
+		}
+		return false
+
+	case *ast.CallExpr:
+		if tv, ok := pass.TypesInfo.Types[e.Fun]; ok && tv.IsType() {
+			if result != 0 || len(e.Args) != 1 || pass.TypesInfo.Types[e.Args[0]].Value != nil {
+				return false
+			}
+			return !isCompared(pass, body, referencedVars(pass, e.Args[0]), body.Lbrace, e.Pos())
+		}
+		if fn := typeutil.StaticCallee(pass.TypesInfo, e); fn != nil {
+			if fact := lookup(fn); fact != nil {
+				return fact.typeOf(result) != ""
+			}
+		}
+	}
+	return false
+}
+
+// assignment is a value assigned to a variable: the given result of expr.
+type assignment struct {
+	expr   ast.Expr
+	result int
+	pos    token.Pos
+}
+
+// assignmentsOf returns the values assigned to a variable in body before a
+// position, by assignments and variable declarations.
+func assignmentsOf(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var, before token.Pos) []assignment {
+	var assignments []assignment
+	record := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, target := range lhs {
+			ident, ok := target.(*ast.Ident)
+			if !ok || pass.TypesInfo.ObjectOf(ident) != v {
+				continue
+			}
+			switch {
+			case len(rhs) == 1 && len(lhs) > 1:
+				assignments = append(assignments, assignment{rhs[0], i, ident.Pos()})
+			case i < len(rhs):
+				assignments = append(assignments, assignment{rhs[i], 0, ident.Pos()})
+			}
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= before {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.ASSIGN || node.Tok == token.DEFINE {
+				record(node.Lhs, node.Rhs)
+			}
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			record(lhs, node.Values)
+		}
+		return true
+	})
+	return assignments
+}
+
+// referencedVars returns the variables referenced by an expression.
+func referencedVars(pass *analysis.Pass, expr ast.Expr) map[*types.Var]bool {
+	vars := make(map[*types.Var]bool)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+				vars[v] = true
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// isCompared reports whether one of the variables is compared, or switched
+// on, in body between from and to by an if or switch statement guarding to:
+// to is in one of its branches, or follows it in the same block when its
+// rejecting branch leaves the block, as in `if n < 0 || n > max { return ... }`.
+func isCompared(pass *analysis.Pass, body *ast.BlockStmt, vars map[*types.Var]bool, from, to token.Pos) bool {
+	compares := func(expr ast.Expr, switched bool) bool {
+		found := false
+		ast.Inspect(expr, func(n ast.Node) bool {
+			operand, ok := n.(ast.Expr)
+			if found || !ok || operand.Pos() <= from || operand.End() > to {
+				return !found
+			}
+			var operands []ast.Expr
+			if switched {
+				operands = []ast.Expr{operand}
+			} else if bin, ok := operand.(*ast.BinaryExpr); ok {
+				switch bin.Op {
+				case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+					operands = []ast.Expr{bin.X, bin.Y}
+				}
+			}
+			for _, operand := range operands {
+				for v := range referencedVars(pass, operand) {
+					found = found || vars[v]
+				}
+			}
+			return !found && !switched
+		})
+		return found
+	}
+	within := func(n ast.Node) bool {
+		return n != nil && n.Pos() <= to && to < n.End()
+	}
+
+	compared := false
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		if compared || n.Pos() >= to {
+			return false
+		}
+		var parent ast.Node
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		stack = append(stack, n)
+
+		var branches []ast.Node
+		var leaves bool
+		switch node := n.(type) {
+		case *ast.IfStmt:
+			if !compares(node.Cond, false) {
+				return true
+			}
+			branches = []ast.Node{node.Body, node.Else}
+			leaves = exitsBlock(pass, node.Body.List)
+		case *ast.SwitchStmt:
+			if node.Tag == nil || !compares(node.Tag, true) {
+				return true
+			}
+			branches = []ast.Node{node.Body}
+			for _, stmt := range node.Body.List {
+				if clause, ok := stmt.(*ast.CaseClause); ok && clause.List == nil {
+					leaves = exitsBlock(pass, clause.Body)
+				}
+			}
+		default:
+			return true
+		}
+
+		for _, branch := range branches {
+			compared = compared || within(branch)
+		}
+		if leaves && to >= n.End() {
+			switch parent.(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+				compared = compared || within(parent)
+			}
+		}
+		return true
+	})
+	return compared
+}
+
+// exitsBlock reports whether a statement list ends by leaving the enclosing
+// block: a return, branch statement or call to panic.
+func exitsBlock(pass *analysis.Pass, stmts []ast.Stmt) bool {
+	if len(stmts) == 0 {
+		return false
+	}
+	switch stmt := stmts[len(stmts)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		if call, ok := stmt.X.(*ast.CallExpr); ok {
+			if id, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+				_, builtin := pass.TypesInfo.Uses[id].(*types.Builtin)
+				return builtin && id.Name == "panic"
+			}
+		}
+	}
+	return false
+}
+
+// unvalidatedUse is a variable holding an unvalidated result of a call.
+type unvalidatedUse struct {
+	callee   *types.Func
+	enumType string
+	pos      token.Pos // where the variable was assigned
+}
+
+// checkUnvalidatedResults reports unvalidated quasi-enum results of functions
+// from other packages that are switched on without a default case, or
+// persisted, without being compared first.
+func checkUnvalidatedResults(pass *analysis.Pass, idx *declIndex) {
+	for _, decl := range idx.funcDecls {
+		vars := make(map[*types.Var]*unvalidatedUse)
+
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				recordUnvalidatedVars(pass, vars, node.Lhs, node.Rhs)
+			case *ast.ValueSpec:
+				lhs := make([]ast.Expr, len(node.Names))
+				for i, name := range node.Names {
+					lhs[i] = name
+				}
+				recordUnvalidatedVars(pass, vars, lhs, node.Values)
+			case *ast.SwitchStmt:
+				if node.Tag != nil && !hasDefaultClause(node.Body) {
+					if use := unvalidatedOperand(pass, decl.Body, vars, node.Tag); use != nil {
+						reportUnvalidatedResult(pass, node.Tag, use, "is switched on without a default case")
+					}
+				}
+			case *ast.CallExpr:
+				checkUnvalidatedPersistence(pass, decl.Body, vars, node)
+			}
+			return true
+		})
+	}
+}
+
+// recordUnvalidatedVars records variables assigned unvalidated results of
+// functions from other packages.
+func recordUnvalidatedVars(pass *analysis.Pass, vars map[*types.Var]*unvalidatedUse, lhs, rhs []ast.Expr) {
+	assign := func(target ast.Expr, call ast.Expr, result int) {
+		ident, ok := target.(*ast.Ident)
+		if !ok {
+			return
+		}
+		v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok {
+			return
+		}
+		delete(vars, v)
+		if use := unvalidatedCall(pass, call, result); use != nil {
+			use.pos = ident.Pos()
+			vars[v] = use
+		}
+	}
+
+	if len(rhs) == 1 && len(lhs) > 1 {
+		for i, target := range lhs {
+			assign(target, rhs[0], i)
+		}
+		return
+	}
+	for i, target := range lhs {
+		if i < len(rhs) {
+			assign(target, rhs[i], 0)
+		}
+	}
+}
+
+// unvalidatedCall returns the use of the given result of a call to a function
+// from another package with unvalidated results, or nil.
+func unvalidatedCall(pass *analysis.Pass, expr ast.Expr, result int) *unvalidatedUse {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg() == pass.Pkg {
+		return nil
+	}
+	fact := importUnvalidatedResult(pass, fn)
+	if fact == nil || fact.typeOf(result) == "" {
+		return nil
+	}
+	return &unvalidatedUse{callee: fn, enumType: fact.typeOf(result), pos: call.Pos()}
+}
+
+// unvalidatedOperand returns the unvalidated result an expression evaluates
+// to, directly or through a variable not compared since its assignment.
+func unvalidatedOperand(pass *analysis.Pass, body *ast.BlockStmt, vars map[*types.Var]*unvalidatedUse, expr ast.Expr) *unvalidatedUse {
+	expr = ast.Unparen(expr)
+	if use := unvalidatedCall(pass, expr, 0); use != nil {
+		return use
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || vars[v] == nil {
+		return nil
+	}
+	if isCompared(pass, body, map[*types.Var]bool{v: true}, vars[v].pos, ident.Pos()) {
+		return nil
+	}
+	return vars[v]
+}
+
+// hasDefaultClause reports whether a switch body has a default clause.
+func hasDefaultClause(body *ast.BlockStmt) bool {
+	for _, stmt := range body.List {
+		if clause, ok := stmt.(*ast.CaseClause); ok && clause.List == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUnvalidatedPersistence reports unvalidated results passed to calls
+// encoding their arguments (see persistenceCalls).
+func checkUnvalidatedPersistence(pass *analysis.Pass, body *ast.BlockStmt, vars map[*types.Var]*unvalidatedUse, call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return
+	}
+	pc, ok := persistenceCalls[fn.FullName()]
+	if !ok || !pc.encode || pc.arg >= len(call.Args) {
+		return
+	}
+	args := call.Args
+	if pc.arg >= 0 {
+		args = args[pc.arg : pc.arg+1]
+	}
+
+	via := strings.Replace(fn.FullName(), fn.Pkg().Path(), fn.Pkg().Name(), 1)
+	for _, arg := range args {
+		if use := unvalidatedOperand(pass, body, vars, arg); use != nil {
+			reportUnvalidatedResult(pass, arg, use, "is persisted via "+via)
+		}
+	}
+}
+
+// reportUnvalidatedResult reports a use of an unvalidated result.
+func reportUnvalidatedResult(pass *analysis.Pass, node ast.Node, use *unvalidatedUse, what string) {
+	callee := strings.Replace(use.callee.FullName(), use.callee.Pkg().Path(), use.callee.Pkg().Name(), 1)
+	report(pass, analysis.Diagnostic{
+		Pos:      node.Pos(),
+		End:      node.End(),
+		Category: CategoryUnvalidatedResult,
+		Message: fmt.Sprintf("result of %s may be outside quasi-enum type %s (converted without validation) and %s",
+			callee, use.enumType, what),
+	})
+}
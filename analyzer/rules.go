@@ -0,0 +1,243 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Rule categories. Every diagnostic carries one of these in
+// analysis.Diagnostic.Category; they are stable and may be used by tools
+// to filter findings. Drivers resolve the rule documentation as
+// Analyzer.URL + "#" + category.
+const (
+	CategoryLiteralAssignment     = "US1-literal-assignment"
+	CategoryLiteralConversion     = "US1-literal-conversion"
+	CategoryLiteralArgument       = "US1-literal-argument"
+	CategoryLiteralCompositeField = "US1-literal-composite-field"
+	CategoryUntypedConstant       = "US2-untyped-constant"
+	CategoryVariableConversion    = "US3-variable-conversion"
+	CategoryUnvalidatedResult     = "US3-unvalidated-result"
+	CategoryForeignConstant       = "US7-foreign-constant"
+
+	CategoryMinConstants   = "DC-001"
+	CategorySameConstBlock = "DC-002"
+	CategorySameFile       = "DC-003"
+	CategoryExclusiveBlock = "DC-004"
+	CategoryProximity      = "DC-005"
+
+	CategoryCompactType       = "QOL-compact-type"
+	CategoryEnumMarker        = "QOL-enum-marker"
+	CategoryStringMethod      = "QOL-string-method"
+	CategoryUnmarshalMethod   = "QOL-unmarshal-method"
+	CategoryPersistMethod     = "QOL-persist-method"
+	CategoryPersistence       = "QOL-persistence"
+	CategoryMethodCoverage    = "QOL-method-coverage"
+	CategoryMethodAsymmetry   = "QOL-method-asymmetry"
+	CategoryStaleStringer     = "QOL-stale-stringer"
+	CategoryUnusedConstant    = "QOL-unused-constant"
+	CategoryStructLayout      = "QOL-struct-layout"
+	CategoryLookupTable       = "QOL-lookup-table"
+	CategoryValueHelpers      = "QOL-value-helpers"
+	CategoryStaleValueHelpers = "QOL-stale-value-helpers"
+
+	CategoryTaintedConversion = "TAINT-unvalidated-conversion"
+
+	CategoryConfiguration = "config"
+)
+
+// Rule filter flags (-only, -skip): comma-separated category globs.
+var (
+	onlyRules string
+	skipRules string
+)
+
+// deprecatedDisableFlags maps the disable-* flags replaced by -skip to the
+// category they suppress.
+var deprecatedDisableFlags = []struct{ name, category string }{
+	{"disable-min-constants-check", CategoryMinConstants},
+	{"disable-same-block-check", CategorySameConstBlock},
+	{"disable-same-file-check", CategorySameFile},
+	{"disable-exclusive-block-check", CategoryExclusiveBlock},
+	{"disable-proximity-check", CategoryProximity},
+	{"disable-uint8-suggestion", CategoryCompactType},
+	{"disable-string-method-check", CategoryStringMethod},
+	{"disable-unmarshal-method-check", CategoryUnmarshalMethod},
+}
+
+// disabledCategories holds the categories suppressed by deprecated
+// disable-* flags; they are added to the -skip patterns.
+var disabledCategories = make(map[string]bool)
+
+// ruleFilters holds the parsed -only, -skip and -severity flags.
+type ruleFilters struct {
+	only, skip []string
+	severities []severityRule
+}
+
+// parsedFilters caches the parsed rule filters; setting one of their flags
+// clears it.
+var parsedFilters atomic.Pointer[ruleFilters]
+
+// filterFlag is a string flag feeding the rule filters.
+type filterFlag struct{ value *string }
+
+func (f filterFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f filterFlag) Set(s string) error {
+	*f.value = s
+	parsedFilters.Store(nil)
+	return nil
+}
+
+// disableFlag is a deprecated boolean flag adding a category to the -skip patterns.
+type disableFlag struct{ category string }
+
+func (f disableFlag) String() string { return strconv.FormatBool(disabledCategories[f.category]) }
+
+func (f disableFlag) IsBoolFlag() bool { return true }
+
+func (f disableFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	disabledCategories[f.category] = v
+	parsedFilters.Store(nil)
+	return nil
+}
+
+// Category returns the stable rule identifier of a usage violation.
+func (vt ViolationType) Category() string {
+	switch vt {
+	case VTLiteralAssignment:
+		return CategoryLiteralAssignment
+	case VTLiteralConversion:
+		return CategoryLiteralConversion
+	case VTLiteralArgument:
+		return CategoryLiteralArgument
+	case VTLiteralCompositeField:
+		return CategoryLiteralCompositeField
+	case VTUntypedConstant:
+		return CategoryUntypedConstant
+	case VTVariableConversion:
+		return CategoryVariableConversion
+	case VTForeignConstant:
+		return CategoryForeignConstant
+	default:
+		return "unknown"
+	}
+}
+
+// Category returns the stable rule identifier of a definition constraint.
+func (dc DefinitionConstraint) Category() string {
+	switch dc {
+	case DC001MinConstants:
+		return CategoryMinConstants
+	case DC002SameConstBlock:
+		return CategorySameConstBlock
+	case DC003SameFile:
+		return CategorySameFile
+	case DC004ExclusiveConstBlock:
+		return CategoryExclusiveBlock
+	case DC005Proximity:
+		return CategoryProximity
+	default:
+		return "unknown"
+	}
+}
+
+// splitPatterns splits a comma-separated list of category globs.
+func splitPatterns(list string) []string {
+	var patterns []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// validatePattern checks that a category glob is well-formed.
+func validatePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid rule pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// validateRuleFilters parses the -only, -skip and -severity flag values,
+// which report and SeverityOf use from then on.
+func validateRuleFilters() error {
+	_, err := currentFilters()
+	return err
+}
+
+// currentFilters returns the parsed rule filters, parsing the flags if they
+// changed since the last call.
+func currentFilters() (*ruleFilters, error) {
+	if filters := parsedFilters.Load(); filters != nil {
+		return filters, nil
+	}
+
+	filters := &ruleFilters{only: splitPatterns(onlyRules), skip: splitPatterns(skipRules)}
+	for _, p := range append(filters.only, filters.skip...) {
+		if err := validatePattern(p); err != nil {
+			return nil, err
+		}
+	}
+	for category, disabled := range disabledCategories {
+		if disabled {
+			filters.skip = append(filters.skip, category)
+		}
+	}
+	sort.Strings(filters.skip)
+
+	var err error
+	if filters.severities, err = parseSeverityOverrides(severityOverrides); err != nil {
+		return nil, err
+	}
+	parsedFilters.Store(filters)
+	return filters, nil
+}
+
+// matchCategory reports whether category matches any of the globs (case-insensitive).
+func matchCategory(patterns []string, category string) bool {
+	category = strings.ToLower(category)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), category); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleEnabled reports whether diagnostics of the given category pass the -only/-skip filters.
+func ruleEnabled(category string) bool {
+	filters, err := currentFilters()
+	if err != nil {
+		return true // reported by run
+	}
+	if len(filters.only) > 0 && !matchCategory(filters.only, category) {
+		return false
+	}
+	return !matchCategory(filters.skip, category)
+}
+
+// report emits a diagnostic unless its category is filtered out.
+func report(pass *analysis.Pass, diag analysis.Diagnostic) {
+	if !ruleEnabled(diag.Category) {
+		return
+	}
+	pass.Report(diag)
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks a finding: unsafe usages are errors, definition problems
+// are warnings and optimization hints are informational.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSeverity parses a severity name (info, warning or error).
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	default:
+		return 0, fmt.Errorf("unknown severity %q (want info, warning or error)", name)
+	}
+}
+
+// severityRule assigns a severity to the categories matching a glob.
+type severityRule struct {
+	pattern  string
+	severity Severity
+}
+
+// defaultSeverities lists the built-in severities; the first match wins.
+var defaultSeverities = []severityRule{
+	{"US*", SeverityError},
+	{"DC-*", SeverityWarning},
+	{CategoryCompactType, SeverityInfo},
+	{CategoryEnumMarker, SeverityInfo},
+	{CategoryStructLayout, SeverityInfo},
+	{"QOL-*", SeverityWarning},
+	{"TAINT-*", SeverityError},
+	{CategoryConfiguration, SeverityError},
+}
+
+// Severity overrides flag (-severity): comma-separated glob=severity pairs.
+var severityOverrides string
+
+// parseSeverityOverrides parses a -severity value such as "QOL-*=info,DC-005=error".
+func parseSeverityOverrides(spec string) ([]severityRule, error) {
+	var rules []severityRule
+	for _, entry := range splitPatterns(spec) {
+		pattern, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid severity override %q (want <category-glob>=<severity>)", entry)
+		}
+		severity, err := ParseSeverity(name)
+		if err != nil {
+			return nil, err
+		}
+		pattern = strings.TrimSpace(pattern)
+		if err := validatePattern(pattern); err != nil {
+			return nil, err
+		}
+		rules = append(rules, severityRule{pattern, severity})
+	}
+	return rules, nil
+}
+
+// SeverityOf returns the severity of a rule category, taking the -severity
+// overrides into account (the last matching override wins).
+func SeverityOf(category string) Severity {
+	var overrides []severityRule
+	if filters, err := currentFilters(); err == nil { // errors are reported by run
+		overrides = filters.severities
+	}
+	for i := len(overrides) - 1; i >= 0; i-- {
+		if matchCategory([]string{overrides[i].pattern}, category) {
+			return overrides[i].severity
+		}
+	}
+	for _, rule := range defaultSeverities {
+		if matchCategory([]string{rule.pattern}, category) {
+			return rule.severity
+		}
+	}
+	return SeverityWarning
+}
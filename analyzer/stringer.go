@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkStaleStringer reports quasi-enums whose stringer-generated String
+// method predates some of their constants. Stringer records every constant
+// in a `func _()` of index assertions (`_ = x[StatusActive-0]`); a constant
+// added later compiles fine but prints as `Status(4)`.
+func checkStaleStringer(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) {
+	if !ruleEnabled(CategoryStaleStringer) {
+		return
+	}
+
+	recorded := make(map[*QuasiEnumType]map[string]bool)
+	assertions := make(map[*QuasiEnumType]*ast.FuncDecl)
+	for _, decl := range idx.stringerAssertions {
+		for _, stmt := range decl.Body.List {
+			c := assertedConstant(pass.TypesInfo, stmt)
+			if c == nil {
+				continue
+			}
+			qe := registry.QuasiEnums[asNamed(c.Type())]
+			if qe == nil {
+				continue
+			}
+			if recorded[qe] == nil {
+				recorded[qe] = make(map[string]bool)
+				assertions[qe] = decl
+			}
+			recorded[qe][c.Name()] = true
+		}
+	}
+
+	for qe, names := range recorded {
+		var missing []string
+		for _, c := range qe.Constants {
+			if c.Name != "_" && !names[c.Name] {
+				missing = append(missing, c.Name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		report(pass, analysis.Diagnostic{
+			Pos:      assertions[qe].Pos(),
+			Category: CategoryStaleStringer,
+			Message: fmt.Sprintf("stringer output for quasi-enum type %s is stale: missing constants %s; re-run stringer",
+				qe.Type.Obj().Name(), strings.Join(missing, ", ")),
+		})
+	}
+}
+
+// assertedConstant returns the constant of a stringer index assertion
+// `_ = x[Name-Value]`, or nil.
+func assertedConstant(info *types.Info, stmt ast.Stmt) *types.Const {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 {
+		return nil
+	}
+	index, ok := assign.Rhs[0].(*ast.IndexExpr)
+	if !ok {
+		return nil
+	}
+	sub, ok := index.Index.(*ast.BinaryExpr)
+	if !ok || sub.Op != token.SUB {
+		return nil
+	}
+	ident, ok := sub.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	c, _ := info.Uses[ident].(*types.Const)
+	return c
+}
+
+// isStringerFile reports whether a file was generated by stringer.
+func isStringerFile(file *ast.File) bool {
+	if !ast.IsGenerated(file) {
+		return false
+	}
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		if strings.Contains(group.Text(), `by "stringer`) {
+			return true
+		}
+	}
+	return false
+}
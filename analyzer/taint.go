@@ -0,0 +1,449 @@
+package analyzer
+
+import (
+	"flag"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+// TaintAnalyzer reports values from untrusted sources that are converted to
+// a quasi-enum type without being compared first. It is more expensive than
+// Analyzer, whose registry it uses, and is therefore run only on request.
+var TaintAnalyzer = &analysis.Analyzer{
+	Name:     "enumtaint",
+	Doc:      "report untrusted input converted to quasi-enum types without validation",
+	URL:      "https://github.com/Djarvur/go-enumsafety/blob/master/docs/rules.md",
+	Requires: []*analysis.Analyzer{Analyzer, buildssa.Analyzer},
+	Run:      runTaint,
+	Flags:    makeTaintFlags(),
+}
+
+// taintSources is the -sources flag of TaintAnalyzer.
+var taintSources string
+
+// defaultTaintSources are the functions whose results (or, with a "#N"
+// suffix, the N-th argument's pointee) are untrusted.
+const defaultTaintSources = "(*net/http.Request).FormValue,(*net/http.Request).PostFormValue," +
+	"(net/url.Values).Get,(net/http.Header).Get," +
+	"strconv.Atoi,strconv.ParseInt,strconv.ParseUint,os.Getenv," +
+	"encoding/json.Unmarshal#1,(*encoding/json.Decoder).Decode#0"
+
+// makeTaintFlags creates the flags of TaintAnalyzer.
+func makeTaintFlags() flag.FlagSet {
+	var fs flag.FlagSet
+	fs.StringVar(&taintSources, "sources", defaultTaintSources,
+		"comma-separated untrusted functions (types.Func.FullName); "+
+			"a '#N' suffix marks the pointee of the N-th argument instead of the result")
+	return fs
+}
+
+// taintSource describes an untrusted function.
+type taintSource struct {
+	arg int // index of the argument whose pointee is untrusted, or -1 for the result
+}
+
+// parseTaintSources parses the -sources flag.
+func parseTaintSources(list string) (map[string]taintSource, error) {
+	sources := make(map[string]taintSource)
+	for _, name := range splitPatterns(list) {
+		source := taintSource{arg: -1}
+		if fn, arg, ok := strings.Cut(name, "#"); ok {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("-sources: invalid argument index in %q", name)
+			}
+			name, source.arg = fn, n
+		}
+		sources[name] = source
+	}
+	return sources, nil
+}
+
+// taintStep is a step of the path from an untrusted source to a value.
+type taintStep struct {
+	value  ssa.Value
+	desc   string
+	pos    token.Pos
+	prev   *taintStep
+	source ssa.Instruction // the source call, for address taint
+}
+
+func runTaint(pass *analysis.Pass) (interface{}, error) {
+	sources, err := parseTaintSources(taintSources)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, _ := pass.ResultOf[Analyzer].(*Catalog)
+	if catalog == nil || catalog.registry == nil || len(catalog.registry.QuasiEnums) == 0 {
+		return nil, nil
+	}
+
+	ssaInput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	for _, fn := range ssaInput.SrcFuncs {
+		checkTaintedConversions(pass, catalog.registry, sources, fn)
+	}
+	return nil, nil
+}
+
+// checkTaintedConversions propagates taint through a function and reports
+// unvalidated conversions of tainted values to quasi-enum types.
+func checkTaintedConversions(pass *analysis.Pass, registry *QuasiEnumRegistry, sources map[string]taintSource, fn *ssa.Function) {
+	taint := make(map[ssa.Value]*taintStep)
+
+	// Propagate until no new value gets tainted (phis may need several rounds)
+	for changed := true; changed; {
+		changed = false
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				value, ok := instr.(ssa.Value)
+				if !ok || taint[value] != nil {
+					continue
+				}
+				if step := taintOf(pass, sources, taint, instr); step != nil && taint[step.value] == nil {
+					taint[step.value] = step
+					changed = true
+				}
+			}
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			target, operand := enumConversion(instr)
+			if target == nil || taint[operand] == nil {
+				continue
+			}
+			qe := registry.QuasiEnums[asNamed(target)]
+			if qe == nil || validated(fn, qe, instr, taint[operand]) {
+				continue
+			}
+			reportTaintedConversion(pass, qe, instr, taint[operand])
+		}
+	}
+}
+
+// taintOf returns the taint step introduced by instr, or nil if it taints
+// nothing. The step taints the value of instr, except for source calls
+// writing through an argument, which taint the argument.
+func taintOf(pass *analysis.Pass, sources map[string]taintSource, taint map[ssa.Value]*taintStep, instr ssa.Instruction) *taintStep {
+	value := instr.(ssa.Value)
+
+	switch v := instr.(type) {
+	case *ssa.Call:
+		return sourceCallTaint(pass, sources, taint, v)
+	case *ssa.Extract:
+		if prev := taint[v.Tuple]; prev != nil && v.Index == 0 {
+			return &taintStep{value: value, prev: prev}
+		}
+		return nil
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if prev := taint[edge]; prev != nil {
+				return &taintStep{value: value, prev: prev}
+			}
+		}
+		return nil
+	case *ssa.UnOp:
+		prev := taint[v.X]
+		if prev == nil {
+			return nil
+		}
+		if v.Op == token.MUL {
+			// A load from an address the source writes through: only after the call
+			if prev.source != nil && !executesAfter(prev.source, instr) {
+				return nil
+			}
+			return &taintStep{value: value, desc: "load", pos: v.Pos(), prev: prev}
+		}
+		if v.Op == token.SUB || v.Op == token.XOR {
+			return &taintStep{value: value, desc: "arithmetic", pos: v.Pos(), prev: prev}
+		}
+		return nil
+	case *ssa.FieldAddr:
+		if prev := taint[v.X]; prev != nil {
+			return &taintStep{value: value, prev: prev, source: prev.source}
+		}
+		return nil
+	case *ssa.IndexAddr:
+		if prev := taint[v.X]; prev != nil {
+			return &taintStep{value: value, prev: prev, source: prev.source}
+		}
+		return nil
+	case *ssa.Field:
+		if prev := taint[v.X]; prev != nil {
+			return &taintStep{value: value, prev: prev}
+		}
+		return nil
+	case *ssa.BinOp:
+		switch v.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+			token.AND, token.OR, token.XOR, token.SHL, token.SHR, token.AND_NOT:
+		default:
+			return nil
+		}
+		for _, operand := range []ssa.Value{v.X, v.Y} {
+			if prev := taint[operand]; prev != nil {
+				return &taintStep{value: value, desc: "arithmetic", pos: v.Pos(), prev: prev}
+			}
+		}
+		return nil
+	case *ssa.Convert, *ssa.ChangeType:
+		operand := instr.Operands(nil)[0]
+		if prev := taint[*operand]; prev != nil {
+			return &taintStep{
+				value: value,
+				desc:  "conversion to " + types.TypeString(value.Type(), types.RelativeTo(pass.Pkg)),
+				pos:   value.Pos(),
+				prev:  prev,
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// sourceCallTaint returns the taint introduced by a call to an untrusted
+// source: of its result, or of the argument pointing to the value it writes.
+func sourceCallTaint(pass *analysis.Pass, sources map[string]taintSource, taint map[ssa.Value]*taintStep, call *ssa.Call) *taintStep {
+	callee := call.Call.StaticCallee()
+	if callee == nil {
+		return nil
+	}
+	obj, ok := callee.Object().(*types.Func)
+	if !ok {
+		return nil
+	}
+	source, ok := sources[obj.FullName()]
+	if !ok {
+		return nil
+	}
+	name := strings.Replace(obj.FullName(), obj.Pkg().Path(), obj.Pkg().Name(), 1)
+
+	if source.arg < 0 {
+		return &taintStep{value: call, desc: name, pos: call.Pos()}
+	}
+
+	// Methods take their receiver as the first argument
+	arg := source.arg
+	if callee.Signature.Recv() != nil {
+		arg++
+	}
+	if arg >= len(call.Call.Args) {
+		return nil
+	}
+	target := call.Call.Args[arg]
+	if mi, ok := target.(*ssa.MakeInterface); ok {
+		target = mi.X
+	}
+	return &taintStep{value: target, desc: name, pos: call.Pos(), source: call}
+}
+
+// enumConversion returns the target type and operand of a conversion, or nil.
+func enumConversion(instr ssa.Instruction) (types.Type, ssa.Value) {
+	switch v := instr.(type) {
+	case *ssa.Convert:
+		return v.Type(), v.X
+	case *ssa.ChangeType:
+		return v.Type(), v.X
+	}
+	return nil, nil
+}
+
+// validated reports whether the conversion is reached only through branch
+// edges proving a value on the tainted path in the range of the quasi-enum:
+// equal to one of its constants, or bounded by both its lowest and highest
+// constants.
+func validated(fn *ssa.Function, qe *QuasiEnumType, sink ssa.Instruction, step *taintStep) bool {
+	path := make(map[ssa.Value]bool)
+	for s := step; s != nil; s = s.prev {
+		path[s.value] = true
+	}
+	low, high, ok := constantRange(qe)
+
+	var lower, upper bool
+	for _, block := range fn.Blocks {
+		if block == sink.Block() || !block.Dominates(sink.Block()) || len(block.Instrs) == 0 {
+			continue
+		}
+		branch, isIf := block.Instrs[len(block.Instrs)-1].(*ssa.If)
+		if !isIf {
+			continue
+		}
+		cond, isBinOp := branch.Cond.(*ssa.BinOp)
+		if !isBinOp {
+			continue
+		}
+		op, proven := provenComparison(block, cond.Op, sink.Block())
+		if !proven {
+			continue
+		}
+
+		// Put the tainted value on the left: c < x is x > c
+		x, y := cond.X, cond.Y
+		if path[y] {
+			op, x, y = flipComparison(op), y, x
+		}
+		c, isConst := y.(*ssa.Const)
+		if !path[x] || !isConst || c.Value == nil {
+			continue
+		}
+
+		switch op {
+		case token.EQL:
+			for _, ec := range qe.Constants {
+				if constantsEqual(c.Value, ec.Value) {
+					return true
+				}
+			}
+		case token.LSS, token.GEQ, token.LEQ, token.GTR:
+			if !ok || !isNumeric(c.Value) {
+				continue
+			}
+			// x < c and x >= c split the values at c, x <= c and x > c at c+1
+			split := c.Value
+			if op == token.LEQ || op == token.GTR {
+				split = constant.BinaryOp(split, token.ADD, constant.MakeInt64(1))
+			}
+			if op == token.GEQ || op == token.GTR {
+				lower = lower || constant.Compare(split, token.EQL, low)
+			} else {
+				upper = upper || constant.Compare(split, token.EQL, constant.BinaryOp(high, token.ADD, constant.MakeInt64(1)))
+			}
+		}
+	}
+	return lower && upper
+}
+
+// provenComparison returns the comparison holding whenever target is
+// reached from the If instruction ending block: op if target is reached only
+// through the true edge, its negation if only through the false edge.
+func provenComparison(block *ssa.BasicBlock, op token.Token, target *ssa.BasicBlock) (token.Token, bool) {
+	if len(block.Succs) != 2 || block.Succs[0] == block.Succs[1] {
+		return op, false
+	}
+	only := func(succ *ssa.BasicBlock) bool {
+		return len(succ.Preds) == 1 && succ.Dominates(target)
+	}
+	switch {
+	case only(block.Succs[0]):
+		return op, true
+	case only(block.Succs[1]):
+		return negateComparison(op), true
+	}
+	return op, false
+}
+
+// negateComparison returns the operator holding when the comparison is false.
+func negateComparison(op token.Token) token.Token {
+	switch op {
+	case token.EQL:
+		return token.NEQ
+	case token.NEQ:
+		return token.EQL
+	case token.LSS:
+		return token.GEQ
+	case token.GEQ:
+		return token.LSS
+	case token.LEQ:
+		return token.GTR
+	case token.GTR:
+		return token.LEQ
+	}
+	return token.ILLEGAL
+}
+
+// flipComparison returns the operator comparing the operands swapped.
+func flipComparison(op token.Token) token.Token {
+	switch op {
+	case token.LSS:
+		return token.GTR
+	case token.GTR:
+		return token.LSS
+	case token.LEQ:
+		return token.GEQ
+	case token.GEQ:
+		return token.LEQ
+	}
+	return op
+}
+
+// constantRange returns the lowest and highest constant values of a
+// numeric quasi-enum.
+func constantRange(qe *QuasiEnumType) (constant.Value, constant.Value, bool) {
+	var low, high constant.Value
+	for _, c := range qe.Constants {
+		if !isNumeric(c.Value) {
+			return nil, nil, false
+		}
+		if low == nil || constant.Compare(c.Value, token.LSS, low) {
+			low = c.Value
+		}
+		if high == nil || constant.Compare(c.Value, token.GTR, high) {
+			high = c.Value
+		}
+	}
+	return low, high, low != nil
+}
+
+// isNumeric reports whether v is an integer or floating-point constant.
+func isNumeric(v constant.Value) bool {
+	return v != nil && (v.Kind() == constant.Int || v.Kind() == constant.Float)
+}
+
+// constantsEqual reports whether two constants of the same kind of type are equal.
+func constantsEqual(x, y constant.Value) bool {
+	switch {
+	case isNumeric(x) && isNumeric(y):
+		return constant.Compare(x, token.EQL, y)
+	case x != nil && y != nil && x.Kind() == constant.String && y.Kind() == constant.String:
+		return constant.StringVal(x) == constant.StringVal(y)
+	}
+	return false
+}
+
+// executesAfter reports whether instr can only execute after the source instruction.
+func executesAfter(source, instr ssa.Instruction) bool {
+	if source.Block() != instr.Block() {
+		return source.Block().Dominates(instr.Block())
+	}
+	for _, i := range source.Block().Instrs {
+		if i == source {
+			return true
+		}
+		if i == instr {
+			return false
+		}
+	}
+	return false
+}
+
+// reportTaintedConversion reports an unvalidated conversion with its source-to-sink path.
+func reportTaintedConversion(pass *analysis.Pass, qe *QuasiEnumType, sink ssa.Instruction, step *taintStep) {
+	var steps []string
+	var source string
+	for s := step; s != nil; s = s.prev {
+		if s.desc == "" {
+			continue
+		}
+		steps = append([]string{fmt.Sprintf("%s (line %d)", s.desc, pass.Fset.Position(s.pos).Line)}, steps...)
+		source = s.desc
+	}
+	name := qe.Type.Obj().Name()
+	steps = append(steps, fmt.Sprintf("conversion to %s (line %d)", name, pass.Fset.Position(sink.Pos()).Line))
+
+	report(pass, analysis.Diagnostic{
+		Pos:      sink.Pos(),
+		Category: CategoryTaintedConversion,
+		Message: fmt.Sprintf("untrusted value from %s is converted to quasi-enum type %s without validation: %s",
+			source, name, strings.Join(steps, " -> ")),
+	})
+}
@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Configuration flags for the unused constant check
+var (
+	checkUnused         bool
+	unusedAllowExported bool
+)
+
+// constantUsageFact records, for one package, the exported quasi-enum
+// constants it declares but does not use itself, and the constants of
+// other packages it references. Main packages combine the facts of all
+// their dependencies to find constants no package of the program uses.
+type constantUsageFact struct {
+	Module     string // path of the package's module
+	Unused     []unusedConstant
+	Referenced []string // keys (see constantKey) of referenced constants of other packages
+}
+
+// unusedConstant describes an exported quasi-enum constant not referenced by its package.
+type unusedConstant struct {
+	Key      string // see constantKey
+	Type     string // name of the quasi-enum type
+	Position string // file:line of the declaration
+}
+
+func (*constantUsageFact) AFact() {}
+
+func (f *constantUsageFact) String() string {
+	return fmt.Sprintf("%d unused, %d referenced", len(f.Unused), len(f.Referenced))
+}
+
+// modulePath returns the path of the module of the analyzed package, or "" if
+// it is unknown or the package belongs to the standard library.
+func modulePath(pass *analysis.Pass) string {
+	if pass.Module == nil {
+		return ""
+	}
+	return pass.Module.Path
+}
+
+// constantKey identifies a constant across packages.
+func constantKey(c *types.Const) string {
+	return c.Pkg().Path() + "." + c.Name()
+}
+
+// checkUnusedConstants reports quasi-enum constants never referenced outside
+// their const declaration, the methods of their type and generated files.
+// Constants only the package itself can use (unexported ones, and all
+// constants of main packages) are reported at their declaration; exported
+// constants of other packages are reported by the main packages importing
+// them, unless -unused-allow-exported is set; only constants of the main
+// package's module are considered. registry may be nil.
+func checkUnusedConstants(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex) {
+	if !checkUnused {
+		return
+	}
+
+	candidates := make(map[string]bool)
+	for _, fact := range pass.AllPackageFacts() {
+		if usage, ok := fact.Fact.(*constantUsageFact); ok {
+			for _, c := range usage.Unused {
+				candidates[c.Key] = true
+			}
+		}
+	}
+
+	referenced := make(map[*types.Const]bool)
+	fact := &constantUsageFact{Module: modulePath(pass)}
+	seen := make(map[string]bool)
+	for ident, obj := range pass.TypesInfo.Uses {
+		c, ok := obj.(*types.Const)
+		if !ok || c.Pkg() == nil || idx.isGenerated(pass.Fset, ident) {
+			continue
+		}
+		if c.Pkg() != pass.Pkg {
+			if key := constantKey(c); candidates[key] && !seen[key] {
+				seen[key] = true
+				fact.Referenced = append(fact.Referenced, key)
+			}
+			continue
+		}
+		if registry != nil && !isDefinitionUse(pass, registry, idx, c, ident) {
+			referenced[c] = true
+		}
+	}
+	sort.Strings(fact.Referenced)
+
+	isMain := pass.Pkg.Name() == "main"
+	if registry != nil {
+		for _, qe := range registry.LocalQuasiEnums() {
+			for _, ec := range qe.Constants {
+				c, ok := pass.Pkg.Scope().Lookup(ec.Name).(*types.Const)
+				if !ok || c.Name() == "_" || referenced[c] {
+					continue
+				}
+				if c.Exported() && !isMain {
+					if !unusedAllowExported && fact.Module != "" {
+						posn := pass.Fset.Position(c.Pos())
+						fact.Unused = append(fact.Unused, unusedConstant{
+							Key:      constantKey(c),
+							Type:     qe.Type.Obj().Name(),
+							Position: fmt.Sprintf("%s:%d", filepath.Base(posn.Filename), posn.Line),
+						})
+					}
+					continue
+				}
+				report(pass, analysis.Diagnostic{
+					Pos:      c.Pos(),
+					Category: CategoryUnusedConstant,
+					Message: fmt.Sprintf("constant %s of quasi-enum type %s is never referenced",
+						c.Name(), qe.Type.Obj().Name()),
+				})
+			}
+		}
+	}
+	sort.Slice(fact.Unused, func(i, j int) bool { return fact.Unused[i].Key < fact.Unused[j].Key })
+
+	if len(fact.Unused) > 0 || len(fact.Referenced) > 0 {
+		pass.ExportPackageFact(fact)
+	}
+
+	if isMain {
+		reportUnusedExported(pass, fact)
+	}
+}
+
+// isDefinitionUse reports whether a reference to a constant lies in the const
+// declaration of its quasi-enum or in a method of the quasi-enum type.
+func isDefinitionUse(pass *analysis.Pass, registry *QuasiEnumRegistry, idx *declIndex, c *types.Const, ident *ast.Ident) bool {
+	qe := registry.QuasiEnums[asNamed(c.Type())]
+	if qe == nil || qe.Imported {
+		return false
+	}
+	within := func(node ast.Node) bool {
+		return node != nil && node.Pos() <= ident.Pos() && ident.End() <= node.End()
+	}
+
+	for _, ec := range qe.Constants {
+		if within(ec.ConstBlock) {
+			return true
+		}
+	}
+	for _, method := range idx.methods[qe.Type] {
+		if within(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportUnusedExported reports, in a main package, the exported quasi-enum
+// constants of its dependencies in the same module that no package of the
+// program references.
+func reportUnusedExported(pass *analysis.Pass, own *constantUsageFact) {
+	if len(pass.Files) == 0 || own.Module == "" {
+		return
+	}
+
+	referenced := make(map[string]bool)
+	for _, key := range own.Referenced {
+		referenced[key] = true
+	}
+	var unused []unusedConstant
+	for _, fact := range pass.AllPackageFacts() {
+		usage, ok := fact.Fact.(*constantUsageFact)
+		if !ok || fact.Package == pass.Pkg {
+			continue
+		}
+		for _, key := range usage.Referenced {
+			referenced[key] = true
+		}
+		if usage.Module == own.Module {
+			unused = append(unused, usage.Unused...)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i].Key < unused[j].Key })
+
+	// The program's constants have no declaration in this package; report
+	// them at its package clause
+	for _, c := range unused {
+		if referenced[c.Key] {
+			continue
+		}
+		report(pass, analysis.Diagnostic{
+			Pos:      pass.Files[0].Name.Pos(),
+			Category: CategoryUnusedConstant,
+			Message: fmt.Sprintf("constant %s of quasi-enum type %s (%s) is never referenced by this program",
+				c.Key, c.Type, c.Position),
+		})
+	}
+}
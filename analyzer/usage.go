@@ -6,13 +6,14 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"path/filepath"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
 )
 
 // buildQuasiEnumType constructs a QuasiEnumType from a detected type.
-func buildQuasiEnumType(pass *analysis.Pass, namedType *types.Named, techniques []DetectionTechnique) *QuasiEnumType {
+func buildQuasiEnumType(idx *declIndex, namedType *types.Named, techniques []DetectionTechnique) *QuasiEnumType {
 	// Find the type definition
 	typeName := namedType.Obj()
 	if typeName == nil {
@@ -25,80 +26,46 @@ func buildQuasiEnumType(pass *analysis.Pass, namedType *types.Named, techniques
 		return nil
 	}
 
-	// Collect constants of this type
-	var constants []EnumConstant
-	var constBlock *ast.GenDecl
+	// Find type declaration
 	var typeDecl *ast.GenDecl
 	var file *ast.File
+	if ts := idx.typesByName[typeName]; ts != nil {
+		typeDecl = ts.decl
+		file = ts.file
+	}
 
-	// Find type declaration and constants
-	for _, f := range pass.Files {
-		// Find type declaration
-		for _, decl := range f.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok {
-				continue
-			}
-
-			// Check for type declaration
-			if genDecl.Tok == token.TYPE {
-				for _, spec := range genDecl.Specs {
-					typeSpec, ok := spec.(*ast.TypeSpec)
-					if !ok {
-						continue
-					}
-					obj := pass.TypesInfo.Defs[typeSpec.Name]
-					if obj != nil && obj.Type() == namedType {
-						typeDecl = genDecl
-						file = f
-					}
-				}
-			}
-
-			// Check for const declarations
-			if genDecl.Tok == token.CONST {
-				for _, spec := range genDecl.Specs {
-					valueSpec, ok := spec.(*ast.ValueSpec)
-					if !ok {
-						continue
-					}
-
-					for i, name := range valueSpec.Names {
-						obj := pass.TypesInfo.Defs[name]
-						if obj == nil {
-							continue
-						}
+	// Collect constants of this type
+	var constants []EnumConstant
+	blockSizes := make(map[*ast.GenDecl]int)
+	var blocks []*ast.GenDecl
+	for _, c := range idx.constsByType[namedType] {
+		expr := ""
+		if c.index < len(c.spec.Values) {
+			expr = types.ExprString(c.spec.Values[c.index])
+		}
 
-						constObj, ok := obj.(*types.Const)
-						if !ok {
-							continue
-						}
+		constants = append(constants, EnumConstant{
+			Name:          c.name.Name,
+			Value:         c.obj.Val(),
+			QuasiEnumType: namedType,
+			Position:      c.name.Pos(),
+			IsIota:        expr == "iota" || expr == "",
+			Expression:    expr,
+			ConstBlock:    c.decl,
+			Object:        c.obj,
+		})
+
+		if blockSizes[c.decl] == 0 {
+			blocks = append(blocks, c.decl)
+		}
+		blockSizes[c.decl]++
+	}
 
-						// Check if this constant is of our enum type
-						if constObj.Type() == namedType {
-							expr := ""
-							if i < len(valueSpec.Values) {
-								expr = types.ExprString(valueSpec.Values[i])
-							}
-
-							constants = append(constants, EnumConstant{
-								Name:          name.Name,
-								Value:         constObj.Val(),
-								QuasiEnumType: namedType,
-								Position:      name.Pos(),
-								IsIota:        expr == "iota" || expr == "",
-								Expression:    expr,
-								ConstBlock:    genDecl,
-							})
-
-							// Track the const block (use the first one found)
-							if constBlock == nil {
-								constBlock = genDecl
-							}
-						}
-					}
-				}
-			}
+	// The primary const block holds most constants (the first one on ties)
+	var constBlock *ast.GenDecl
+	for _, block := range blocks {
+		if constBlock == nil || blockSizes[block] > blockSizes[constBlock] {
+			constBlock = block
 		}
 	}
 
@@ -113,10 +80,10 @@ func buildQuasiEnumType(pass *analysis.Pass, namedType *types.Named, techniques
 		TypeDecl:       typeDecl,
 		ConstBlock:     constBlock,
 		File:           file,
-	}
 
-	// Detect helper methods (US5, US6)
-	detectHelperMethods(namedType, qe)
+		// FR-047: only DT-001 found it, an explicit marker should be suggested
+		SuggestEnumComment: len(techniques) == 1 && techniques[0] == DT001ConstantsBased,
+	}
 
 	return qe
 }
@@ -176,7 +143,7 @@ func checkVarDecl(pass *analysis.Pass, registry *QuasiEnumRegistry, decl *ast.Ge
 			}
 
 			// Check for untyped constant (US2)
-			if ident, ok := value.(*ast.Ident); ok {
+			if ident := namedIdent(value); ident != nil {
 				if isUntypedConstant(pass, registry, ident, varType) {
 					reportUsageViolation(pass, registry, value, varType, VTUntypedConstant)
 					continue
@@ -231,7 +198,7 @@ func checkAssignment(pass *analysis.Pass, registry *QuasiEnumRegistry, stmt *ast
 		}
 
 		// Check for untyped constant (US2)
-		if ident, ok := rhs.(*ast.Ident); ok {
+		if ident := namedIdent(rhs); ident != nil {
 			if isUntypedConstant(pass, registry, ident, lhsType) {
 				reportUsageViolation(pass, registry, rhs, lhsType, VTUntypedConstant)
 				continue
@@ -271,7 +238,7 @@ func checkCallExpr(pass *analysis.Pass, registry *QuasiEnumRegistry, call *ast.C
 		}
 
 		// Check for untyped constant (US2)
-		if ident, ok := arg.(*ast.Ident); ok {
+		if ident := namedIdent(arg); ident != nil {
 			if isUntypedConstant(pass, registry, ident, paramType) {
 				reportUsageViolation(pass, registry, arg, paramType, VTUntypedConstant)
 				continue
@@ -329,6 +296,43 @@ func checkCompositeLit(pass *analysis.Pass, registry *QuasiEnumRegistry, lit *as
 	}
 }
 
+// registerAliases registers the aliases of imported enum constants found by
+// detectAnalyzer and the constants declared in functions whose initializer
+// refers to an enum constant, such as `const fallback = StatusActive`.
+func registerAliases(pass *analysis.Pass, registry *QuasiEnumRegistry, det *detection) {
+	for c, member := range det.aliases {
+		registry.Aliases[c] = member
+	}
+	for _, c := range det.idx.localConsts {
+		if c.index >= len(c.spec.Values) {
+			continue
+		}
+		if ident := namedIdent(ast.Unparen(c.spec.Values[c.index])); ident != nil {
+			member := registry.ResolveConstant(pass.TypesInfo.Uses[ident])
+			if member != nil && member.QuasiEnumType == asNamed(c.obj.Type()) {
+				registry.Aliases[c.obj] = member
+			}
+		}
+	}
+}
+
+// checkForeignConstants reports constants of imported quasi-enum types
+// declared in this package: they are not members of the enum, whatever
+// their name, unless they are aliases of one.
+func checkForeignConstants(pass *analysis.Pass, registry *QuasiEnumRegistry) {
+	for ident, obj := range pass.TypesInfo.Defs {
+		c, ok := obj.(*types.Const)
+		if !ok {
+			continue
+		}
+		qe := registry.QuasiEnums[asNamed(c.Type())]
+		if qe == nil || !qe.Imported || registry.ResolveConstant(c) != nil {
+			continue
+		}
+		reportUsageViolation(pass, registry, ident, qe.Type, VTForeignConstant)
+	}
+}
+
 // isUntypedConstant checks if an identifier is an untyped constant that's not a valid enum value.
 // This implements US2: detecting untyped constants that aren't part of the enum definition.
 func isUntypedConstant(pass *analysis.Pass, registry *QuasiEnumRegistry, ident *ast.Ident, enumType types.Type) bool {
@@ -339,13 +343,12 @@ func isUntypedConstant(pass *analysis.Pass, registry *QuasiEnumRegistry, ident *
 	}
 
 	// Check if it's a constant
-	_, ok := obj.(*types.Const)
-	if !ok {
+	if _, ok := obj.(*types.Const); !ok {
 		return false
 	}
 
 	// Get the named enum type
-	namedType, ok := enumType.(*types.Named)
+	namedType, ok := types.Unalias(enumType).(*types.Named)
 	if !ok {
 		return false
 	}
@@ -356,12 +359,10 @@ func isUntypedConstant(pass *analysis.Pass, registry *QuasiEnumRegistry, ident *
 		return false
 	}
 
-	// Check if the constant is in the enum's constant list
-	for _, enumConst := range qe.Constants {
-		if enumConst.Name == ident.Name {
-			// This is a valid enum constant
-			return false
-		}
+	// Check if the constant is one of the enum's constants (by identity, not
+	// name) or an alias of one
+	if member := registry.ResolveConstant(obj); member != nil && member.QuasiEnumType == namedType {
+		return false
 	}
 
 	// This is a constant, but not one of the enum's defined constants
@@ -389,7 +390,7 @@ func isVariableConversion(pass *analysis.Pass, registry *QuasiEnumRegistry, iden
 	varType := varObj.Type()
 
 	// Get the named enum type
-	namedEnumType, ok := enumType.(*types.Named)
+	namedEnumType, ok := types.Unalias(enumType).(*types.Named)
 	if !ok {
 		return false
 	}
@@ -401,10 +402,8 @@ func isVariableConversion(pass *analysis.Pass, registry *QuasiEnumRegistry, iden
 	}
 
 	// Check if the variable is one of the enum constants (shouldn't happen for variables, but be safe)
-	for _, enumConst := range qe.Constants {
-		if enumConst.Name == ident.Name {
-			return false
-		}
+	if qe.HasConstant(obj) {
+		return false
 	}
 
 	// Check if the variable type matches the enum's underlying type
@@ -415,7 +414,7 @@ func isVariableConversion(pass *analysis.Pass, registry *QuasiEnumRegistry, iden
 
 	// Check if the variable is of a different enum type with the same underlying type
 	// This catches: var s Status = StatusActive; Priority(s)
-	if namedVarType, ok := varType.(*types.Named); ok {
+	if namedVarType, ok := types.Unalias(varType).(*types.Named); ok {
 		if registry.IsQuasiEnumType(namedVarType) {
 			// It's a different enum type - this is a cross-enum conversion
 			return true
@@ -438,16 +437,28 @@ func isLiteralValue(pass *analysis.Pass, expr ast.Expr) bool {
 		return false
 	}
 
-	// If it's a constant value but not an identifier, it's a literal
-	if tv.Value != nil {
-		if _, isIdent := expr.(*ast.Ident); !isIdent {
-			return true
-		}
+	// If it's a constant value but not a (qualified) identifier, it's a literal
+	if tv.Value != nil && namedIdent(expr) == nil {
+		return true
 	}
 
 	return false
 }
 
+// namedIdent returns the identifier of an identifier or qualified identifier
+// (pkg.Name) expression, or nil.
+func namedIdent(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.SelectorExpr:
+		if _, ok := e.X.(*ast.Ident); ok {
+			return e.Sel
+		}
+	}
+	return nil
+}
+
 // isTypeConversion checks if a call expression is a type conversion.
 func isTypeConversion(pass *analysis.Pass, call *ast.CallExpr, targetType types.Type) bool {
 	// Type conversions have exactly one argument
@@ -456,10 +467,10 @@ func isTypeConversion(pass *analysis.Pass, call *ast.CallExpr, targetType types.
 	}
 
 	// Check if the function is actually a type
-	if ident, ok := call.Fun.(*ast.Ident); ok {
+	if ident := namedIdent(call.Fun); ident != nil {
 		obj := pass.TypesInfo.Uses[ident]
 		if typeName, ok := obj.(*types.TypeName); ok {
-			return typeName.Type() == targetType
+			return types.Unalias(typeName.Type()) == types.Unalias(targetType)
 		}
 	}
 
@@ -468,7 +479,7 @@ func isTypeConversion(pass *analysis.Pass, call *ast.CallExpr, targetType types.
 
 // reportUsageViolation reports a usage violation.
 func reportUsageViolation(pass *analysis.Pass, registry *QuasiEnumRegistry, node ast.Node, enumType types.Type, violationType ViolationType) {
-	namedType := enumType.(*types.Named)
+	namedType := asNamed(enumType)
 	qe := registry.QuasiEnums[namedType]
 	if qe == nil {
 		return
@@ -480,14 +491,88 @@ func reportUsageViolation(pass *analysis.Pass, registry *QuasiEnumRegistry, node
 		validConstants[i] = c.Name
 	}
 
-	msg := formatUsageViolation(violationType, namedType.Obj().Name(), validConstants)
-	pass.Reportf(node.Pos(), "%s", msg)
+	report(pass, analysis.Diagnostic{
+		Pos:      node.Pos(),
+		Category: violationType.Category(),
+		Message:  formatUsageViolation(violationType, namedType.Obj().Name(), validConstants),
+	})
 }
 
 // reportConstraintViolation reports a definition constraint violation.
-func reportConstraintViolation(pass *analysis.Pass, qe *QuasiEnumType, violation DefinitionConstraint) {
-	msg := formatConstraintViolation(qe.Type.Obj().Name(), violation)
-	pass.Reportf(qe.Position, "%s", msg)
+func reportConstraintViolation(pass *analysis.Pass, registry *QuasiEnumRegistry, qe *QuasiEnumType, violation DefinitionConstraint) {
+	var fixes []analysis.SuggestedFix
+	switch violation {
+	case DC002SameConstBlock:
+		reportStrayConstants(pass, qe)
+		return
+	case DC003SameFile:
+		if fix := sameFileFix(pass, qe); fix != nil {
+			fixes = append(fixes, *fix)
+		}
+	case DC004ExclusiveConstBlock:
+		if fix := exclusiveBlockFix(pass, registry, qe.ConstBlock); fix != nil {
+			fixes = append(fixes, *fix)
+		}
+	case DC005Proximity:
+		reportProximityViolation(pass, registry.ConstraintConfig, qe)
+		return
+	}
+
+	report(pass, analysis.Diagnostic{
+		Pos:            qe.Position,
+		Category:       violation.Category(),
+		Message:        formatConstraintViolation(qe.Type.Obj().Name(), violation),
+		SuggestedFixes: fixes,
+	})
+}
+
+// reportStrayConstants reports each constant declared outside the primary const block (DC-002).
+func reportStrayConstants(pass *analysis.Pass, qe *QuasiEnumType) {
+	var fixes []analysis.SuggestedFix
+	if fix := sameConstBlockFix(pass, qe); fix != nil {
+		fixes = append(fixes, *fix)
+	}
+
+	for _, c := range strayConstants(qe) {
+		report(pass, analysis.Diagnostic{
+			Pos:            c.Position,
+			Category:       DC002SameConstBlock.Category(),
+			Message:        formatStrayConstant(qe.Type.Obj().Name(), c.Name),
+			SuggestedFixes: fixes,
+		})
+	}
+}
+
+// reportProximityViolation reports a DC-005 violation with a fix moving the const block.
+func reportProximityViolation(pass *analysis.Pass, config *ConstraintConfig, qe *QuasiEnumType) {
+	issue, gap, between := checkProximity(qe, config, qe.TypeDecl, qe.ConstBlock, pass.Fset, qe.File, pass.TypesInfo)
+
+	var detail string
+	switch issue {
+	case proximityOtherFile:
+		detail = fmt.Sprintf("const block is in another file (%s)", filepath.Base(pass.Fset.Position(qe.ConstBlock.Pos()).Filename))
+	case proximityDeclBetween:
+		detail = fmt.Sprintf("%s sits between type definition and const block", describeDecl(between, pass.TypesInfo))
+	case proximityTooFar:
+		detail = fmt.Sprintf("const block is %d lines from the type definition (max %d)", gap, config.ProximityLines)
+	default:
+		return
+	}
+	msg := fmt.Sprintf("quasi-enum type %s violates %s: %s", qe.Type.Obj().Name(), DC005Proximity.String(), detail)
+
+	var fixes []analysis.SuggestedFix
+	if issue != proximityOtherFile {
+		if fix := proximityFix(pass, qe); fix != nil {
+			fixes = append(fixes, *fix)
+		}
+	}
+
+	report(pass, analysis.Diagnostic{
+		Pos:            qe.Position,
+		Category:       DC005Proximity.Category(),
+		Message:        msg,
+		SuggestedFixes: fixes,
+	})
 }
 
 // formatUsageViolation formats a usage violation message.
@@ -505,6 +590,8 @@ func formatUsageViolation(vt ViolationType, typeName string, validConstants []st
 		return formatMessage("untyped constant assigned to quasi-enum type %s; use one of: %v", typeName, validConstants)
 	case VTVariableConversion:
 		return formatMessage("variable converted to quasi-enum type %s; use one of: %v", typeName, validConstants)
+	case VTForeignConstant:
+		return formatMessage("constant of quasi-enum type %s declared outside its package; use one of: %v", typeName, validConstants)
 	default:
 		return formatMessage("invalid usage of quasi-enum type %s", typeName, validConstants)
 	}
@@ -521,13 +608,17 @@ func formatConstraintViolation(typeName string, dc DefinitionConstraint) string
 		return formatMessage("quasi-enum type %s violates %s: type and constants must be in the same file", typeName, dc.String())
 	case DC004ExclusiveConstBlock:
 		return formatMessage("quasi-enum type %s violates %s: const block must contain only constants of this type", typeName, dc.String())
-	case DC005Proximity:
-		return formatMessage("quasi-enum type %s violates %s: type definition and const block must be adjacent", typeName, dc.String())
 	default:
 		return formatMessage("quasi-enum type %s violates constraint %s", typeName, dc.String())
 	}
 }
 
+// formatStrayConstant formats a DC-002 message for a constant outside the primary const block.
+func formatStrayConstant(typeName string, constName string) string {
+	return fmt.Sprintf("quasi-enum type %s violates %s: constant %s is declared outside the primary const block",
+		typeName, DC002SameConstBlock.String(), constName)
+}
+
 // formatMessage is a helper to format messages consistently.
 func formatMessage(format string, args ...interface{}) string {
 	// For now, just use a simple format
@@ -18,6 +18,7 @@ const (
 	VTLiteralCompositeField
 	VTUntypedConstant
 	VTVariableConversion
+	VTForeignConstant
 
 	// Constraint violation
 	VTConstraint
@@ -37,6 +38,8 @@ func (vt ViolationType) String() string {
 		return "untyped constant"
 	case VTVariableConversion:
 		return "variable conversion"
+	case VTForeignConstant:
+		return "foreign constant"
 	case VTConstraint:
 		return "constraint violation"
 	default:
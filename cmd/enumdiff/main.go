@@ -0,0 +1,143 @@
+// Package main provides enumdiff, which compares the quasi-enum catalogs of
+// two versions of a module and reports changes that can corrupt persisted
+// values: removed enums and constants, changed values, values reused by
+// other constants, and changed underlying types.
+//
+// Each version is given as a catalog JSON file (see -export), a directory
+// holding a working tree, or a git revision of the current repository:
+//
+//	enumdiff v1.2.0 .                 # last release against the working tree
+//	enumdiff -export=enums.json       # write the catalog of the working tree
+//	enumdiff enums.json HEAD          # stored catalog against HEAD
+//
+// The command exits with status 3 when breaking changes are found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+	"github.com/Djarvur/go-enumsafety/internal/enumcatalog"
+)
+
+// Exit codes, matching the enumsafety command.
+const (
+	exitOK       = 0
+	exitFailure  = 1
+	exitBreaking = 3
+)
+
+var (
+	export   = flag.String("export", "", "write the catalog of the given version (default: current directory) to this file ('-' for stdout) and exit")
+	patterns = flag.String("packages", "./...", "comma-separated package patterns to analyze, relative to each version's directory")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "enumdiff: report breaking changes of quasi-enum values\n\n"+
+			"Usage: enumdiff [-flag] OLD NEW\n       enumdiff -export=FILE [VERSION]\n\n"+
+			"A version is a catalog JSON file, a directory, or a git revision.\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	os.Exit(run())
+}
+
+// run executes the command and returns its exit code.
+func run() int {
+	if *export != "" {
+		if flag.NArg() > 1 {
+			flag.Usage()
+			return exitFailure
+		}
+		version := "."
+		if flag.NArg() == 1 {
+			version = flag.Arg(0)
+		}
+		if err := exportCatalog(version, *export); err != nil {
+			fmt.Fprintf(os.Stderr, "enumdiff: %v\n", err)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		return exitFailure
+	}
+	oldCat, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "enumdiff: %s: %v\n", flag.Arg(0), err)
+		return exitFailure
+	}
+	newCat, err := load(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "enumdiff: %s: %v\n", flag.Arg(1), err)
+		return exitFailure
+	}
+
+	var breaking, compatible []enumcatalog.Change
+	for _, change := range enumcatalog.Diff(oldCat, newCat) {
+		if change.Kind.Breaking() {
+			breaking = append(breaking, change)
+		} else {
+			compatible = append(compatible, change)
+		}
+	}
+	printChanges("Incompatible changes:", breaking)
+	printChanges("Compatible changes:", compatible)
+
+	if len(breaking) > 0 {
+		return exitBreaking
+	}
+	return exitOK
+}
+
+// load returns the catalog of a version: a catalog file, a directory, or a git revision.
+func load(version string) (*analyzer.Catalog, error) {
+	pkgs := strings.Split(*patterns, ",")
+	info, err := os.Stat(version)
+	switch {
+	case err == nil && info.IsDir():
+		return enumcatalog.Load(version, pkgs...)
+	case err == nil:
+		return enumcatalog.Read(version)
+	default:
+		return enumcatalog.LoadRevision(version, pkgs...)
+	}
+}
+
+// exportCatalog writes the catalog of a version to path.
+func exportCatalog(version, path string) error {
+	catalog, err := load(version)
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		return enumcatalog.Write(os.Stdout, catalog)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := enumcatalog.Write(f, catalog); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printChanges prints a titled list of changes, if any.
+func printChanges(title string, changes []enumcatalog.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Println(title)
+	for _, change := range changes {
+		fmt.Printf("- %s\n", change)
+	}
+}
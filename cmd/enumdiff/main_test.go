@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const statusSource = `package p
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+`
+
+// gitRepo creates a repository holding a module whose status.go goes
+// through the given versions, one commit each, and changes to it.
+func gitRepo(t *testing.T, versions ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/p\n\ngo 1.24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range versions {
+		if err := os.WriteFile(filepath.Join(dir, "status.go"), []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", "-A")
+		runGit(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update")
+	}
+	t.Chdir(dir)
+}
+
+// runGit runs a git command in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// runArgs runs the command with the given arguments and returns its exit code.
+func runArgs(t *testing.T, args ...string) int {
+	t.Helper()
+	if err := flag.CommandLine.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return run()
+}
+
+func TestExitCode(t *testing.T) {
+	renumbered := strings.Replace(statusSource, "StatusActive Status = iota", "StatusActive Status = iota + 1", 1)
+	added := strings.Replace(statusSource, "\tStatusInactive\n", "\tStatusInactive\n\tStatusPaused\n", 1)
+	gitRepo(t, statusSource, added, renumbered)
+
+	tests := []struct {
+		old, new string
+		want     int
+	}{
+		{"HEAD~2", "HEAD~1", exitOK},     // constant added
+		{"HEAD~1", "HEAD", exitBreaking}, // values changed
+		{"HEAD~2", ".", exitBreaking},    // against the working tree
+		{"HEAD", "no-such-revision", exitFailure},
+	}
+	for _, tt := range tests {
+		if got := runArgs(t, tt.old, tt.new); got != tt.want {
+			t.Errorf("enumdiff %s %s: exit code %d, want %d", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestExportAndCompare(t *testing.T) {
+	gitRepo(t, statusSource)
+	t.Cleanup(func() { *export = "" })
+	catalog := filepath.Join(t.TempDir(), "enums.json")
+	if got := runArgs(t, "-export="+catalog); got != exitOK {
+		t.Fatalf("enumdiff -export: exit code %d, want %d", got, exitOK)
+	}
+	*export = ""
+
+	renamed := strings.ReplaceAll(statusSource, "StatusInactive", "StatusPaused")
+	if err := os.WriteFile("status.go", []byte(renamed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := runArgs(t, catalog, "."); got != exitBreaking {
+		t.Errorf("enumdiff after renaming a constant: exit code %d, want %d", got, exitBreaking)
+	}
+}
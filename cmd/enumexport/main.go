@@ -0,0 +1,115 @@
+// Package main provides enumexport, which writes definitions of the
+// quasi-enum types detected by the enumsafety analyzer for other languages
+// and API specs, so that they list the same values as the code:
+//
+//	enumexport -format=openapi ./api/... > enums.openapi.json
+//	enumexport -format=jsonschema -values=values -o enums.schema.json
+//	enumexport -format=typescript -trimprefix -o web/src/enums.ts
+//	enumexport -format=proto -proto-package=jobs.v1 -o proto/enums.proto
+//
+// JSON Schema and OpenAPI schemas list the names produced by the MarshalText
+// or String method of each enum (or its constant values), the constant names
+// in x-enum-varnames and their doc comments in x-enum-descriptions.
+// TypeScript enums or unions and proto enums list the constants ordered by
+// value.
+//
+// With -verify, the output file is compared with what would be written: the
+// command prints the differences and exits with status 3 if it is stale.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Djarvur/go-enumsafety/internal/enumcatalog"
+	"github.com/Djarvur/go-enumsafety/internal/enumexport"
+	"github.com/Djarvur/go-enumsafety/internal/enumgen"
+)
+
+// Exit codes, matching the enumsafety command.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitStale   = 3
+)
+
+var (
+	format       = flag.String("format", enumexport.FormatJSONSchema, "output format: jsonschema, openapi, typescript or proto")
+	values       = flag.String("values", enumexport.ValuesAuto, "enum values: names (from MarshalText or String), values, or auto (names when every constant has one); proto always uses values")
+	tsStyle      = flag.String("ts-style", enumexport.TSEnum, "TypeScript declarations: enum or union")
+	trimPrefix   = flag.Bool("trimprefix", false, "remove the type name from the start of TypeScript and proto member names")
+	nameCase     = flag.String("case", "", "case of TypeScript and proto member names: lower, upper, snake, kebab or upper-snake (default: as declared for TypeScript, upper-snake for proto)")
+	protoPackage = flag.String("proto-package", "", "package declared by the proto file")
+	output       = flag.String("o", "", "write the output to this file (default: stdout)")
+	verify       = flag.Bool("verify", false, "compare the -o file with the output instead of writing it, and fail if it is stale")
+)
+
+// errStale reports an output file that differs from the generated output.
+var errStale = errors.New("stale")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "enumexport: write definitions of quasi-enum types\n\n"+
+			"Usage: enumexport [-flag] [package...]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if !enumexport.ValidValues(*values) || !enumgen.ValidCase(*nameCase) || *verify && *output == "" {
+		flag.Usage()
+		os.Exit(exitFailure)
+	}
+	switch err := run(); {
+	case errors.Is(err, errStale):
+		fmt.Fprintf(os.Stderr, "enumexport: %s is stale; rerun enumexport without -verify\n", *output)
+		os.Exit(exitStale)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "enumexport: %v\n", err)
+		os.Exit(exitFailure)
+	}
+	os.Exit(exitOK)
+}
+
+// run writes, or verifies, the definitions of the quasi-enums of the
+// selected packages.
+func run() error {
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	catalog, err := enumcatalog.Load(".", patterns...)
+	if err != nil {
+		return err
+	}
+
+	src, err := enumexport.Render(catalog, enumexport.Options{
+		Format:       *format,
+		Values:       *values,
+		TSStyle:      *tsStyle,
+		TrimPrefix:   *trimPrefix,
+		Case:         *nameCase,
+		ProtoPackage: *protoPackage,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *verify:
+		existing, err := os.ReadFile(*output)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if diff := enumexport.Diff(*output, *output+" (generated)", existing, src); diff != "" {
+			fmt.Print(diff)
+			return errStale
+		}
+		return nil
+	case *output == "":
+		_, err = os.Stdout.Write(src)
+		return err
+	default:
+		return os.WriteFile(*output, src, 0o644)
+	}
+}
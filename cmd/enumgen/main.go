@@ -0,0 +1,209 @@
+// Package main provides enumgen, which generates helper methods for the
+// quasi-enum types of a package, as detected by the enumsafety analyzer:
+// String, Parse<Type>, MarshalText, UnmarshalText, IsValid, Values and,
+// optionally, the Scan and Value methods of sql.Scanner and driver.Valuer.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate go run github.com/Djarvur/go-enumsafety/cmd/enumgen
+//
+// The helpers of each type are written to <type>_enum.go in the package
+// directory. Methods the type already declares in other files are not
+// generated. Type errors in files written by enumgen, e.g. references to
+// renamed constants, are ignored, so that they can be regenerated.
+//
+// Types are configured by //enumgen: directives in their doc or line
+// comment, overriding the flags:
+//
+//	// Status enum
+//	//enumgen:sql trimprefix case=snake
+//	type Status uint8
+//
+// Directives: sql, skip, trimprefix (the type name), trimprefix=Prefix and
+// case=lower|upper|snake|kebab|upper-snake.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+	"github.com/Djarvur/go-enumsafety/internal/enumcatalog"
+	"github.com/Djarvur/go-enumsafety/internal/enumgen"
+)
+
+var (
+	typeNames  = flag.String("type", "", "comma-separated quasi-enum types to generate (default: all)")
+	sql        = flag.Bool("sql", false, "generate sql.Scanner and driver.Valuer methods")
+	trimPrefix = flag.Bool("trimprefix", false, "remove the type name from the start of constant names")
+	nameCase   = flag.String("case", "", "case of the names: lower, upper, snake, kebab or upper-snake (default: as declared)")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "enumgen: generate helper methods for quasi-enum types\n\n"+
+			"Usage: enumgen [-flag] [package]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 || !enumgen.ValidCase(*nameCase) {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "enumgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the helpers of the selected quasi-enums of the package.
+func run() error {
+	pattern := "."
+	if flag.NArg() == 1 {
+		pattern = flag.Arg(0)
+	}
+	// Files written by enumgen may refer to renamed or removed constants
+	// until they are regenerated
+	pkgs, err := enumcatalog.LoadPackages(".", inGeneratedFile, pattern)
+	if err != nil {
+		return err
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("%s matches %d packages, want one", pattern, len(pkgs))
+	}
+	pkg := pkgs[0]
+	catalog, err := enumcatalog.Analyze(pkgs)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(*typeNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			selected[name] = true
+		}
+	}
+
+	for _, enum := range catalog.Enums {
+		if enum.Package != pkg.PkgPath || len(selected) > 0 && !selected[enum.Name] {
+			continue
+		}
+		delete(selected, enum.Name)
+		if err := generate(pkg, enum); err != nil {
+			return err
+		}
+	}
+	if len(selected) > 0 {
+		missing := slices.Sorted(maps.Keys(selected))
+		return fmt.Errorf("not quasi-enum types of %s: %s", pkg.PkgPath, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// errorPos matches the position of a package error, "file:line:col".
+var errorPos = regexp.MustCompile(`^(.+?)(?::\d+){1,2}$`)
+
+// inGeneratedFile reports whether a package error is located in a file
+// written by enumgen.
+func inGeneratedFile(e packages.Error) bool {
+	m := errorPos.FindStringSubmatch(e.Pos)
+	if m == nil {
+		return false
+	}
+	f, err := os.Open(m[1])
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(enumgen.Header))
+	_, err = io.ReadFull(f, header)
+	return err == nil && string(header) == enumgen.Header
+}
+
+// generate writes the helpers of one quasi-enum.
+func generate(pkg *packages.Package, enum analyzer.CatalogEnum) error {
+	spec, decl, file := findTypeSpec(pkg, enum.Name)
+	if spec == nil {
+		return fmt.Errorf("%s: type declaration not found", enum.Name)
+	}
+
+	opts := enumgen.Options{SQL: *sql, Case: *nameCase}
+	if *trimPrefix {
+		opts.TrimPrefix = enum.Name
+	}
+	groups := []*ast.CommentGroup{spec.Doc, spec.Comment}
+	if len(decl.Specs) == 1 {
+		groups = append(groups, decl.Doc)
+	}
+	opts, err := enumgen.ParseDirectives(enum.Name, opts, groups...)
+	if err != nil {
+		return err
+	}
+	if opts.Skip {
+		return nil
+	}
+
+	dir := filepath.Dir(pkg.Fset.Position(file.Package).Filename)
+	output := filepath.Join(dir, strings.ToLower(enum.Name)+"_enum.go")
+	opts.Omit = declared(pkg, enum.Name, output)
+
+	src, err := enumgen.Generate(pkg.Name, enum, opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
+
+// findTypeSpec returns the declaration of a package-level type.
+func findTypeSpec(pkg *packages.Package, name string) (*ast.TypeSpec, *ast.GenDecl, *ast.File) {
+	for _, file := range pkg.Syntax {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range decl.Specs {
+				if spec := s.(*ast.TypeSpec); spec.Name.Name == name {
+					return spec, decl, file
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// declared returns the generated methods and parse function that the package
+// already declares outside the output file, which is regenerated.
+func declared(pkg *packages.Package, typeName, output string) map[string]bool {
+	obj, _ := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if obj == nil {
+		return nil
+	}
+
+	outside := func(o types.Object) bool {
+		return o != nil && pkg.Fset.Position(o.Pos()).Filename != output
+	}
+	result := make(map[string]bool)
+	mset := types.NewMethodSet(types.NewPointer(obj.Type()))
+	for _, name := range []string{"String", "MarshalText", "UnmarshalText", "IsValid", "Values", "Scan", "Value"} {
+		if sel := mset.Lookup(pkg.Types, name); sel != nil && outside(sel.Obj()) {
+			result[name] = true
+		}
+	}
+	if parse := enumgen.ParseFunc(typeName); outside(pkg.Types.Scope().Lookup(parse)) {
+		result[parse] = true
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Djarvur/go-enumsafety/internal/enumgen"
+)
+
+const statusSource = `package p
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+`
+
+// writeModule writes the files of a module to a temporary directory and
+// changes to it.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/p\n\ngo 1.24\n"
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+	return dir
+}
+
+// readFile returns the content of a file, failing the test on error.
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRegenerateAfterRename(t *testing.T) {
+	dir := writeModule(t, map[string]string{"status.go": statusSource})
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The generated file refers to StatusInactive, and another file to the
+	// generated parser
+	renamed := strings.ReplaceAll(statusSource, "StatusInactive", "StatusPaused") +
+		"\nfunc parse(s string) (Status, error) { return ParseStatus(s) }\n"
+	if err := os.WriteFile(filepath.Join(dir, "status.go"), []byte(renamed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(); err != nil {
+		t.Fatalf("run() after renaming a constant: %v", err)
+	}
+
+	got := readFile(t, filepath.Join(dir, "status_enum.go"))
+	if !strings.Contains(got, "case StatusPaused:") || strings.Contains(got, "StatusInactive") {
+		t.Errorf("status_enum.go was not regenerated:\n%s", got)
+	}
+}
+
+func TestLoadErrorsOutsideGeneratedFiles(t *testing.T) {
+	writeModule(t, map[string]string{
+		"status.go": statusSource + "\nvar broken = StatusUnknown\n",
+	})
+	if err := run(); err == nil || !strings.Contains(err.Error(), "undefined: StatusUnknown") {
+		t.Errorf("run() error = %v, want undefined StatusUnknown", err)
+	}
+}
+
+func TestGenerateWritesOutputFile(t *testing.T) {
+	dir := writeModule(t, map[string]string{"status.go": statusSource})
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readFile(t, filepath.Join(dir, "status_enum.go"))
+	if !strings.HasPrefix(got, enumgen.Header+"\n") {
+		t.Errorf("status_enum.go does not start with %q:\n%s", enumgen.Header, got)
+	}
+	for _, want := range []string{"package p", "func (s Status) String() string", "func ParseStatus(name string) (Status, error)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("status_enum.go lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	tests := []struct {
+		name, source string
+		generated    bool
+		sql          bool
+	}{
+		{
+			name:      "no directive",
+			source:    statusSource,
+			generated: true,
+		},
+		{
+			name:   "skip in doc comment",
+			source: strings.Replace(statusSource, "// Status enum\n", "// Status enum\n//enumgen:skip\n", 1),
+		},
+		{
+			name:      "sql in line comment",
+			source:    strings.Replace(statusSource, "type Status uint8\n", "type Status uint8 //enumgen:sql\n", 1),
+			generated: true,
+			sql:       true,
+		},
+		{
+			name:      "sql in doc of single-spec declaration",
+			source:    strings.Replace(statusSource, "// Status enum\ntype Status uint8\n", "//enumgen:sql\ntype (\n\t// Status enum\n\tStatus uint8\n)\n", 1),
+			generated: true,
+			sql:       true,
+		},
+		{
+			name: "sql in doc of multi-spec declaration",
+			source: strings.Replace(statusSource, "// Status enum\ntype Status uint8\n",
+				"//enumgen:sql\ntype (\n\t// Status enum\n\tStatus uint8\n\n\tName string\n)\n", 1),
+			generated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeModule(t, map[string]string{"status.go": tt.source})
+			if err := run(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(filepath.Join(dir, "status_enum.go"))
+			if generated := err == nil; generated != tt.generated {
+				t.Fatalf("status_enum.go generated = %v, want %v", generated, tt.generated)
+			}
+			if sql := strings.Contains(string(got), ") Scan(src any) error"); sql != tt.sql {
+				t.Errorf("Scan generated = %v, want %v:\n%s", sql, tt.sql, got)
+			}
+		})
+	}
+}
+
+func TestDeclaredMethodsNotGenerated(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"status.go": statusSource,
+		"format.go": `package p
+
+import "errors"
+
+func (s Status) String() string { return "status" }
+
+func ParseStatus(s string) (Status, error) { return 0, errors.New("unsupported") }
+`,
+	})
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+	// Regenerating must not count the methods of the previous output as declared
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readFile(t, filepath.Join(dir, "status_enum.go"))
+	for _, unwanted := range []string{") String() string", "func ParseStatus("} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("status_enum.go redeclares %q:\n%s", unwanted, got)
+		}
+	}
+	for _, want := range []string{") MarshalText() ([]byte, error)", ") IsValid() bool", ") Values() []Status"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("status_enum.go lacks %q:\n%s", want, got)
+		}
+	}
+}
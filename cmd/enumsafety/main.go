@@ -1,12 +1,212 @@
 // Package main provides the CLI entry point for go-enumsafety.
+//
+// Findings are printed with their severity and rule category, and the exit
+// code is controlled by -fail-on: the command exits with status 3 only when
+// a finding at or above that severity exists. When invoked by go vet, or
+// with one of the standard driver flags (-fix, -diff, -json, -c), the
+// command falls back to the standard singlechecker driver.
+//
+// With -taint, the command also runs the taint analyzer, which reports
+// untrusted input converted to quasi-enum types without validation. Its
+// flags are prefixed with "taint." (e.g. -taint.sources).
+//
+// -fail-on and -taint are rejected by the standard driver, whose output and
+// exit codes ignore them.
 package main
 
 import (
+	"flag"
+	"fmt"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
 	"golang.org/x/tools/go/analysis/singlechecker"
+	"golang.org/x/tools/go/packages"
 
 	"github.com/Djarvur/go-enumsafety/analyzer"
 )
 
+// Exit codes, matching the standard analysis drivers.
+const (
+	exitOK       = 0
+	exitFailure  = 1
+	exitFindings = 3
+)
+
+// standardDriverFlags are handled by singlechecker rather than by this driver.
+var standardDriverFlags = map[string]bool{
+	"fix": true, "diff": true, "json": true, "c": true, "V": true, "flags": true,
+}
+
 func main() {
-	singlechecker.Main(analyzer.Analyzer)
+	if useStandardDriver(os.Args[1:]) {
+		if name := driverOnlyFlag(os.Args[1:]); name != "" {
+			fmt.Fprintf(os.Stderr, "%s: -%s is not supported with -fix, -diff, -json, -c or under go vet\n",
+				analyzer.Analyzer.Name, name)
+			os.Exit(exitFailure)
+		}
+		singlechecker.Main(analyzer.Analyzer)
+		return
+	}
+	os.Exit(run())
+}
+
+// useStandardDriver reports whether the command line belongs to go vet
+// (a trailing *.cfg argument) or requests a standard driver feature.
+func useStandardDriver(args []string) bool {
+	if len(args) > 0 && strings.HasSuffix(args[len(args)-1], ".cfg") {
+		return true
+	}
+	for _, name := range flagNames(args) {
+		if standardDriverFlags[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// driverOnlyFlag returns the first flag of args that only this driver
+// implements, or "".
+func driverOnlyFlag(args []string) string {
+	for _, name := range flagNames(args) {
+		if name == "fail-on" || name == "taint" || strings.HasPrefix(name, "taint.") {
+			return name
+		}
+	}
+	return ""
+}
+
+// flagNames returns the names of the flags at the start of args.
+func flagNames(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		names = append(names, name)
+	}
+	return names
+}
+
+// run analyzes the packages named on the command line and prints the findings.
+func run() int {
+	failOn := flag.String("fail-on", "info",
+		"exit non-zero only for findings at or above this severity (info, warning, error)")
+	tests := flag.Bool("test", true, "indicates whether test files should be analyzed, too")
+	taint := flag.Bool("taint", false, "also report untrusted input converted to quasi-enum types without validation")
+	_ = flag.Bool("v", false, "no effect (deprecated)")
+	analyzer.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+	analyzer.TaintAnalyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, "taint."+f.Name, f.Usage)
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s: %s\n\nUsage: %s [-flag] [package]\n\nFlags:\n",
+			analyzer.Analyzer.Name, analyzer.Analyzer.Doc, analyzer.Analyzer.Name)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	threshold, err := analyzer.ParseSeverity(*failOn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: -fail-on: %v\n", analyzer.Analyzer.Name, err)
+		return exitFailure
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		return exitFailure
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: *tests}, flag.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", analyzer.Analyzer.Name, err)
+		return exitFailure
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return exitFailure
+	}
+
+	roots := []*analysis.Analyzer{analyzer.Analyzer}
+	if *taint {
+		roots = append(roots, analyzer.TaintAnalyzer)
+	}
+	graph, err := checker.Analyze(roots, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", analyzer.Analyzer.Name, err)
+		return exitFailure
+	}
+
+	findings, failed := collectFindings(graph)
+	exit := exitOK
+	if failed {
+		exit = exitFailure
+	}
+	for _, f := range findings {
+		fmt.Printf("%s: %s: %s [%s]\n", f.position, f.severity, f.diag.Message, f.diag.Category)
+		if f.severity >= threshold && exit == exitOK {
+			exit = exitFindings
+		}
+	}
+	return exit
+}
+
+// finding is a diagnostic resolved to a position and severity.
+type finding struct {
+	position token.Position
+	severity analyzer.Severity
+	diag     analysis.Diagnostic
+}
+
+// collectFindings gathers the root diagnostics of the graph, de-duplicated
+// (files shared by a package and its test variant are analyzed twice) and
+// sorted by position. It reports whether any analysis failed.
+func collectFindings(graph *checker.Graph) ([]finding, bool) {
+	type key struct {
+		position token.Position
+		message  string
+	}
+	seen := make(map[key]bool)
+	failed := false
+
+	var findings []finding
+	for act := range graph.All() {
+		if act.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", act, act.Err)
+			failed = true
+			continue
+		}
+		if !act.IsRoot {
+			continue
+		}
+		for _, diag := range act.Diagnostics {
+			posn := act.Package.Fset.Position(diag.Pos)
+			k := key{posn, diag.Message}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			findings = append(findings, finding{posn, analyzer.SeverityOf(diag.Category), diag})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].position, findings[j].position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return findings[i].diag.Message < findings[j].diag.Message
+	})
+	return findings, failed
 }
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runMainEnv makes the test binary run the command instead of the tests.
+const runMainEnv = "ENUMSAFETY_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(exitOK)
+	}
+	os.Exit(m.Run())
+}
+
+// Sources whose most severe finding is informational, a warning or an error.
+const (
+	infoSource = `package p
+
+// Level enum
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+func (l Level) String() string { return "" }
+
+func (l *Level) UnmarshalText([]byte) error { return nil }
+`
+	warningSource = `package p
+
+// Level enum
+type Level uint8
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+func (l Level) String() string { return "" }
+`
+	errorSource = `package p
+
+// Level enum
+type Level uint8
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+func (l Level) String() string { return "" }
+
+func (l *Level) UnmarshalText([]byte) error { return nil }
+
+func use(Level) {}
+
+func call() { use(1) }
+`
+)
+
+// writeModule writes a single-file module to a temporary directory and
+// returns the path of its source file.
+func writeModule(t *testing.T, source string) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/p\n\ngo 1.24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// runCommand runs the command in dir and returns its exit code, standard
+// output and standard error.
+func runCommand(t *testing.T, dir string, args ...string) (int, string, string) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatal(err)
+	}
+	return cmd.ProcessState.ExitCode(), stdout.String(), stderr.String()
+}
+
+func TestFailOn(t *testing.T) {
+	tests := []struct {
+		name, source string
+		want         map[string]int // exit code by -fail-on level
+	}{
+		{"info", infoSource, map[string]int{"info": exitFindings, "warning": exitOK, "error": exitOK}},
+		{"warning", warningSource, map[string]int{"info": exitFindings, "warning": exitFindings, "error": exitOK}},
+		{"error", errorSource, map[string]int{"info": exitFindings, "warning": exitFindings, "error": exitFindings}},
+	}
+	for _, tt := range tests {
+		file := writeModule(t, tt.source)
+		for level, want := range tt.want {
+			code, stdout, stderr := runCommand(t, filepath.Dir(file), "-fail-on="+level, "./...")
+			if code != want {
+				t.Errorf("%s source, -fail-on=%s: exit code %d, want %d\nstdout:\n%s\nstderr:\n%s",
+					tt.name, level, code, want, stdout, stderr)
+			}
+		}
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	file := writeModule(t, errorSource)
+	code, stdout, _ := runCommand(t, filepath.Dir(file), "./...")
+	if code != exitFindings {
+		t.Errorf("exit code %d, want %d", code, exitFindings)
+	}
+	want := file + ":17:19: error: literal value passed as quasi-enum type Level; use one of: LevelLow, LevelHigh [US1-literal-argument]\n"
+	if stdout != want {
+		t.Errorf("output =\n%s\nwant\n%s", stdout, want)
+	}
+}
+
+func TestInvalidFailOn(t *testing.T) {
+	file := writeModule(t, infoSource)
+	code, _, stderr := runCommand(t, filepath.Dir(file), "-fail-on=fatal", "./...")
+	if code != exitFailure || !strings.Contains(stderr, `-fail-on: unknown severity "fatal"`) {
+		t.Errorf("exit code %d, stderr %q; want %d and an unknown severity error", code, stderr, exitFailure)
+	}
+}
+
+func TestStandardDriver(t *testing.T) {
+	file := writeModule(t, errorSource)
+	dir := filepath.Dir(file)
+
+	code, stdout, stderr := runCommand(t, dir, "-json", "./...")
+	var tree map[string]map[string]any
+	if err := json.Unmarshal([]byte(stdout), &tree); err != nil {
+		t.Fatalf("-json output is not JSON (exit code %d): %v\nstdout:\n%s\nstderr:\n%s", code, err, stdout, stderr)
+	}
+	if code != exitOK || !strings.Contains(stdout, "US1-literal-argument") {
+		t.Errorf("-json: exit code %d, output:\n%s\nwant %d and the US1 finding", code, stdout, exitOK)
+	}
+
+	for _, args := range [][]string{
+		{"-fail-on=error", "-fix", "./..."},
+		{"-fix", "-taint", "./..."},
+	} {
+		code, _, stderr := runCommand(t, dir, args...)
+		if code != exitFailure || !strings.Contains(stderr, "is not supported with -fix") {
+			t.Errorf("%v: exit code %d, stderr %q; want %d and a rejection", args, code, stderr, exitFailure)
+		}
+	}
+}
+
+func TestUseStandardDriver(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"./..."}, false},
+		{[]string{"-fail-on=warning", "./..."}, false},
+		{[]string{"-fix", "./..."}, true},
+		{[]string{"--diff", "./..."}, true},
+		{[]string{"-json", "./..."}, true},
+		{[]string{"-c=2", "./..."}, true},
+		{[]string{"-flags"}, true},
+		{[]string{"/tmp/go-build/vet.cfg"}, true},
+		{[]string{"-taint", "./...", "-fix"}, false}, // flags end at the first package
+	}
+	for _, tt := range tests {
+		if got := useStandardDriver(tt.args); got != tt.want {
+			t.Errorf("useStandardDriver(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package enumcatalog
+
+import (
+	"fmt"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+// ChangeKind classifies a difference between two catalogs.
+type ChangeKind int
+
+const (
+	EnumRemoved       ChangeKind = iota // A quasi-enum type no longer exists
+	ConstantRemoved                     // A constant no longer exists
+	ValueChanged                        // A constant has a different value
+	ValueReused                         // A value of a removed or renumbered constant is taken by another one
+	UnderlyingChanged                   // The underlying type changed
+	EnumAdded                           // A new quasi-enum type
+	ConstantAdded                       // A new constant with a fresh value
+)
+
+// Breaking reports whether the change can corrupt persisted values or break callers.
+func (k ChangeKind) Breaking() bool {
+	return k != EnumAdded && k != ConstantAdded
+}
+
+// Change is a difference between two catalogs.
+type Change struct {
+	Kind    ChangeKind
+	Enum    string // Qualified type name, e.g. "example.com/pkg.Status"
+	Message string
+}
+
+// String returns the change in "pkg.Type: message" form.
+func (c Change) String() string {
+	return c.Enum + ": " + c.Message
+}
+
+// Diff compares two catalogs and returns the changes from old to new,
+// ordered by enum and, within an enum, by the old declaration order.
+func Diff(old, new *analyzer.Catalog) []Change {
+	newEnums := make(map[string]*analyzer.CatalogEnum)
+	for i := range new.Enums {
+		newEnums[qualifiedName(&new.Enums[i])] = &new.Enums[i]
+	}
+
+	var changes []Change
+	for i := range old.Enums {
+		oldEnum := &old.Enums[i]
+		name := qualifiedName(oldEnum)
+		newEnum, ok := newEnums[name]
+		if !ok {
+			changes = append(changes, Change{EnumRemoved, name, "enum removed"})
+			continue
+		}
+		delete(newEnums, name)
+		changes = append(changes, diffEnum(name, oldEnum, newEnum)...)
+	}
+
+	for i := range new.Enums {
+		if name := qualifiedName(&new.Enums[i]); newEnums[name] != nil {
+			changes = append(changes, Change{EnumAdded, name, "enum added"})
+		}
+	}
+	return changes
+}
+
+// diffEnum compares two versions of a quasi-enum type.
+func diffEnum(name string, old, new *analyzer.CatalogEnum) []Change {
+	var changes []Change
+	if old.Underlying != new.Underlying {
+		changes = append(changes, Change{UnderlyingChanged, name,
+			fmt.Sprintf("underlying type changed from %s to %s", old.Underlying, new.Underlying)})
+	}
+
+	oldValues := make(map[string]string, len(old.Constants))
+	oldOwners := make(map[string][]string) // value -> old constant names
+	for _, c := range old.Constants {
+		oldValues[c.Name] = c.Value
+		oldOwners[c.Value] = append(oldOwners[c.Value], c.Name)
+	}
+	newValues := make(map[string]string, len(new.Constants))
+	for _, c := range new.Constants {
+		newValues[c.Name] = c.Value
+	}
+
+	// released holds the old constants whose value is no longer theirs
+	released := make(map[string]bool)
+	for _, c := range old.Constants {
+		value, ok := newValues[c.Name]
+		switch {
+		case !ok:
+			released[c.Name] = true
+			changes = append(changes, Change{ConstantRemoved, name,
+				fmt.Sprintf("constant %s removed (was %s)", c.Name, c.Value)})
+		case value != c.Value:
+			released[c.Name] = true
+			changes = append(changes, Change{ValueChanged, name,
+				fmt.Sprintf("constant %s changed value from %s to %s", c.Name, c.Value, value)})
+		}
+	}
+
+	for _, c := range new.Constants {
+		if oldValues[c.Name] == c.Value {
+			continue
+		}
+		reused := ""
+		for _, owner := range oldOwners[c.Value] {
+			if owner != c.Name && released[owner] {
+				reused = owner
+				break
+			}
+		}
+		switch _, existed := oldValues[c.Name]; {
+		case reused != "":
+			changes = append(changes, Change{ValueReused, name,
+				fmt.Sprintf("constant %s reuses value %s of former constant %s", c.Name, c.Value, reused)})
+		case !existed:
+			changes = append(changes, Change{ConstantAdded, name,
+				fmt.Sprintf("constant %s added (= %s)", c.Name, c.Value)})
+		}
+	}
+	return changes
+}
+
+// qualifiedName returns the package-qualified name of an enum.
+func qualifiedName(enum *analyzer.CatalogEnum) string {
+	return enum.Package + "." + enum.Name
+}
@@ -0,0 +1,90 @@
+package enumcatalog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+func TestDiff(t *testing.T) {
+	status := func(underlying string, consts ...string) analyzer.CatalogEnum {
+		enum := analyzer.CatalogEnum{Package: "example.com/p", Name: "Status", Underlying: underlying}
+		for i := 0; i < len(consts); i += 2 {
+			enum.Constants = append(enum.Constants, analyzer.CatalogConstant{Name: consts[i], Value: consts[i+1]})
+		}
+		return enum
+	}
+	catalog := func(enums ...analyzer.CatalogEnum) *analyzer.Catalog {
+		return &analyzer.Catalog{Enums: enums}
+	}
+
+	tests := []struct {
+		name     string
+		old, new *analyzer.Catalog
+		want     []string
+	}{
+		{
+			name: "unchanged",
+			old:  catalog(status("int", "A", "0", "B", "1")),
+			new:  catalog(status("int", "A", "0", "B", "1")),
+		},
+		{
+			name: "added",
+			old:  catalog(),
+			new:  catalog(status("int", "A", "0", "B", "1")),
+			want: []string{"example.com/p.Status: enum added"},
+		},
+		{
+			name: "removed enum",
+			old:  catalog(status("int", "A", "0", "B", "1")),
+			new:  catalog(),
+			want: []string{"example.com/p.Status: enum removed"},
+		},
+		{
+			name: "renumbered",
+			old:  catalog(status("int", "A", "0", "B", "1", "C", "2")),
+			new:  catalog(status("uint8", "A", "0", "C", "1", "D", "3")),
+			want: []string{
+				"example.com/p.Status: underlying type changed from int to uint8",
+				"example.com/p.Status: constant B removed (was 1)",
+				"example.com/p.Status: constant C changed value from 2 to 1",
+				"example.com/p.Status: constant C reuses value 1 of former constant B",
+				"example.com/p.Status: constant D added (= 3)",
+			},
+		},
+		{
+			name: "reused by new constant",
+			old:  catalog(status("int", "A", "0", "B", "1")),
+			new:  catalog(status("int", "A", "0", "X", "1")),
+			want: []string{
+				"example.com/p.Status: constant B removed (was 1)",
+				"example.com/p.Status: constant X reuses value 1 of former constant B",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, change := range Diff(tt.old, tt.new) {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeKindBreaking(t *testing.T) {
+	for _, kind := range []ChangeKind{EnumRemoved, ConstantRemoved, ValueChanged, ValueReused, UnderlyingChanged} {
+		if !kind.Breaking() {
+			t.Errorf("kind %d should be breaking", kind)
+		}
+	}
+	for _, kind := range []ChangeKind{EnumAdded, ConstantAdded} {
+		if kind.Breaking() {
+			t.Errorf("kind %d should not be breaking", kind)
+		}
+	}
+}
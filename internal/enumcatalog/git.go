@@ -0,0 +1,110 @@
+package enumcatalog
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+// LoadRevision extracts the git revision rev of the repository containing
+// the current directory into a temporary directory and loads the catalog of
+// the packages matching patterns there. Patterns are resolved relative to
+// the current directory's location within the repository.
+func LoadRevision(rev string, patterns ...string) (*analyzer.Catalog, error) {
+	prefix, err := git("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	top, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "enumdiff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	archive, err := gitOutput("-C", top, "archive", "--format=tar", rev)
+	if err != nil {
+		return nil, err
+	}
+	if err := extractTar(bytes.NewReader(archive), tmp); err != nil {
+		return nil, fmt.Errorf("extracting %s: %w", rev, err)
+	}
+
+	return Load(filepath.Join(tmp, filepath.FromSlash(prefix)), patterns...)
+}
+
+// git runs a git command and returns its output with surrounding newlines trimmed.
+func git(args ...string) (string, error) {
+	out, err := gitOutput(args...)
+	return strings.Trim(string(out), "\n"), err
+}
+
+// gitOutput runs a git command and returns its raw output.
+func gitOutput(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// extractTar writes the directories and regular files of a tar stream below dst.
+func extractTar(r io.Reader, dst string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unsafe path %q in archive", hdr.Name)
+		}
+		path := filepath.Join(dst, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeFile creates path, including missing parent directories, with the content of r.
+func writeFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,101 @@
+package enumcatalog
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	statusV1 = `package p
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+`
+	statusV2 = `package p
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusPaused
+)
+`
+)
+
+// commitFiles writes files below dir and commits them, initializing the
+// repository on the first call.
+func commitFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		runGit(t, dir, "init", "-q")
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update")
+}
+
+// runGit runs a git command in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestLoadRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	// The module sits in a subdirectory of the repository
+	commitFiles(t, dir, map[string]string{
+		"svc/go.mod":    "module example.com/p\n\ngo 1.24\n",
+		"svc/status.go": statusV1,
+	})
+	commitFiles(t, dir, map[string]string{"svc/status.go": statusV2})
+	t.Chdir(filepath.Join(dir, "svc"))
+
+	oldCat, err := LoadRevision("HEAD~1", "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCat, err := LoadRevision("HEAD", "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, change := range Diff(oldCat, newCat) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"example.com/p.Status: constant StatusInactive removed (was 1)",
+		"example.com/p.Status: constant StatusPaused reuses value 1 of former constant StatusInactive",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(HEAD~1, HEAD) = %q, want %q", got, want)
+	}
+
+	if _, err := LoadRevision("no-such-revision", "./..."); err == nil {
+		t.Error("LoadRevision accepted an unknown revision")
+	}
+}
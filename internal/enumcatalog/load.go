@@ -0,0 +1,93 @@
+// Package enumcatalog loads, stores and compares quasi-enum catalogs
+// produced by the enumsafety analyzer.
+package enumcatalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+// Load runs the analyzer over the packages matching patterns in dir and
+// merges their catalogs. Test files are not analyzed.
+func Load(dir string, patterns ...string) (*analyzer.Catalog, error) {
+	pkgs, err := LoadPackages(dir, nil, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(pkgs)
+}
+
+// LoadPackages loads the packages matching patterns in dir with their
+// syntax and type information, failing on any package error that tolerate,
+// if not nil, rejects. Packages whose errors are all tolerated are analyzed
+// as if they had none.
+func LoadPackages(dir string, tolerate func(packages.Error) bool, patterns ...string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var loadErrs []packages.Error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		tolerated := 0
+		for _, e := range pkg.Errors {
+			if tolerate != nil && tolerate(e) {
+				tolerated++
+				continue
+			}
+			loadErrs = append(loadErrs, e)
+		}
+		if tolerated > 0 && tolerated == len(pkg.Errors) {
+			pkg.IllTyped = false
+		}
+	})
+	if len(loadErrs) > 0 {
+		return nil, fmt.Errorf("loading packages: %v", loadErrs[0])
+	}
+	return pkgs, nil
+}
+
+// Analyze runs the analyzer over loaded packages and merges their catalogs.
+func Analyze(pkgs []*packages.Package) (*analyzer.Catalog, error) {
+	graph, err := checker.Analyze([]*analysis.Analyzer{analyzer.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &analyzer.Catalog{Enums: []analyzer.CatalogEnum{}}
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("%s: %w", act, act.Err)
+		}
+		catalog.Merge(act.Result.(*analyzer.Catalog))
+	}
+	return catalog, nil
+}
+
+// Read decodes a catalog from a JSON file.
+func Read(path string) (*analyzer.Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var catalog analyzer.Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	catalog.Sort()
+	return &catalog, nil
+}
+
+// Write encodes a catalog as indented JSON.
+func Write(w io.Writer, catalog *analyzer.Catalog) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(catalog)
+}
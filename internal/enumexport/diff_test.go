@@ -0,0 +1,35 @@
+package enumexport
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	if got := Diff("a", "b", []byte("x\ny\n"), []byte("x\ny\n")); got != "" {
+		t.Errorf("Diff() of equal files = %q, want none", got)
+	}
+
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := `--- a
++++ b
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if got := Diff("a", "b", []byte(old), []byte(new)); got != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, want := Diff("a", "b", nil, []byte("x\n")), "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+x\n"; got != want {
+		t.Errorf("Diff() from empty =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,346 @@
+// Package enumexport renders the quasi-enums of an enumsafety catalog as
+// schema definitions for API specifications, TypeScript types and protobuf
+// enums, so that the values listed there are those the analyzer detects in
+// the code.
+package enumexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+	"github.com/Djarvur/go-enumsafety/internal/enumgen"
+)
+
+// Output formats.
+const (
+	FormatJSONSchema = "jsonschema" // JSON Schema document with one $defs entry per enum
+	FormatOpenAPI    = "openapi"    // OpenAPI document fragment with one component schema per enum
+	FormatTypeScript = "typescript" // TypeScript module with one enum or union type per enum
+	FormatProto      = "proto"      // proto3 file with one enum per enum
+)
+
+// TypeScript styles.
+const (
+	TSEnum  = "enum"  // export enum Status { Active = "active", ... }
+	TSUnion = "union" // export type Status = "active" | ...
+)
+
+// Header is the first line of the TypeScript and proto files.
+const Header = "// Code generated by enumexport; DO NOT EDIT."
+
+// Value modes, selecting what the enum values of a schema are.
+const (
+	ValuesAuto   = "auto"   // names if every constant has one, values otherwise
+	ValuesNames  = "names"  // names produced by MarshalText or String
+	ValuesValues = "values" // constant values
+)
+
+// JSONSchemaDialect is the $schema of JSON Schema documents.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Options configure the rendering of a catalog.
+type Options struct {
+	Format string // FormatJSONSchema, FormatOpenAPI, FormatTypeScript or FormatProto
+	Values string // ValuesAuto (if empty), ValuesNames or ValuesValues; proto enums always use values
+
+	// TypeScript and proto only
+	TSStyle      string // TSEnum (if empty) or TSUnion
+	TrimPrefix   bool   // remove the type name from constant names
+	Case         string // case of constant names (see enumgen.Name); proto defaults to "upper-snake"
+	ProtoPackage string // package declared by the proto file, if any
+}
+
+// Schema is the schema of one quasi-enum type. Constants sharing a value are
+// listed once, by their first name.
+type Schema struct {
+	Type         string   `json:"type"`
+	Description  string   `json:"description,omitempty"`
+	Enum         []any    `json:"enum"`
+	VarNames     []string `json:"x-enum-varnames"`
+	Descriptions []string `json:"x-enum-descriptions,omitempty"`
+}
+
+// Render returns the file describing the enums of the catalog in the format
+// of the options.
+func Render(catalog *analyzer.Catalog, opts Options) ([]byte, error) {
+	if !enumgen.ValidCase(opts.Case) {
+		return nil, fmt.Errorf("unknown case %q", opts.Case)
+	}
+	switch opts.Format {
+	case FormatJSONSchema, FormatOpenAPI:
+		return renderJSON(catalog, opts)
+	case FormatTypeScript:
+		return renderTypeScript(catalog, opts)
+	case FormatProto:
+		return renderProto(catalog, opts)
+	default:
+		return nil, fmt.Errorf("unknown format %q", opts.Format)
+	}
+}
+
+// renderJSON returns the indented JSON Schema or OpenAPI document.
+func renderJSON(catalog *analyzer.Catalog, opts Options) ([]byte, error) {
+	schemas, err := Schemas(catalog, opts.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if opts.Format == FormatJSONSchema {
+		doc = struct {
+			Schema string            `json:"$schema"`
+			Defs   map[string]Schema `json:"$defs"`
+		}{JSONSchemaDialect, schemas}
+	} else {
+		type components struct {
+			Schemas map[string]Schema `json:"schemas"`
+		}
+		doc = struct {
+			Components components `json:"components"`
+		}{components{schemas}}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Schemas returns the schemas of the enums of the catalog by name. Enums are
+// named by their type name, qualified by their package path (with dots for
+// slashes) when several packages declare the same name.
+func Schemas(catalog *analyzer.Catalog, values string) (map[string]Schema, error) {
+	if values == "" {
+		values = ValuesAuto
+	}
+	if !ValidValues(values) {
+		return nil, fmt.Errorf("unknown value mode %q", values)
+	}
+
+	count := make(map[string]int)
+	for _, enum := range catalog.Enums {
+		count[enum.Name]++
+	}
+
+	schemas := make(map[string]Schema, len(catalog.Enums))
+	for _, enum := range catalog.Enums {
+		schema, err := NewSchema(enum, values)
+		if err != nil {
+			return nil, err
+		}
+		name := enum.Name
+		if count[name] > 1 {
+			name = strings.ReplaceAll(enum.Package, "/", ".") + "." + name
+		}
+		schemas[name] = schema
+	}
+	return schemas, nil
+}
+
+// ValidValues reports whether v names a value mode.
+func ValidValues(v string) bool {
+	switch v {
+	case ValuesAuto, ValuesNames, ValuesValues:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewSchema returns the schema of an enum with the given value mode.
+func NewSchema(enum analyzer.CatalogEnum, values string) (Schema, error) {
+	constants := Distinct(enum)
+	typ, vals, err := enumValues(enum, constants, values)
+	if err != nil {
+		return Schema{}, err
+	}
+
+	schema := Schema{Type: typ, Description: enum.Doc, Enum: vals, VarNames: []string{}}
+	hasDoc := false
+	for _, c := range constants {
+		schema.VarNames = append(schema.VarNames, c.Name)
+		schema.Descriptions = append(schema.Descriptions, c.Doc)
+		hasDoc = hasDoc || c.Doc != ""
+	}
+	if !hasDoc {
+		schema.Descriptions = nil
+	}
+	return schema, nil
+}
+
+// enumValues returns the JSON type and the values of constants of an enum
+// with the given value mode: strings, or json.Number for numeric values.
+func enumValues(enum analyzer.CatalogEnum, constants []analyzer.CatalogConstant, values string) (string, []any, error) {
+	if values == "" || values == ValuesAuto {
+		values = ValuesNames
+		for _, c := range constants {
+			if c.Text == "" {
+				values = ValuesValues
+				break
+			}
+		}
+	}
+
+	typ := "string"
+	if values == ValuesValues {
+		var err error
+		if typ, err = jsonType(enum.Underlying); err != nil {
+			return "", nil, fmt.Errorf("%s.%s: %w", enum.Package, enum.Name, err)
+		}
+	}
+
+	result := make([]any, 0, len(constants))
+	for _, c := range constants {
+		switch {
+		case values == ValuesNames && c.Text == "":
+			return "", nil, fmt.Errorf("%s.%s: constant %s has no name; add a String or MarshalText method handling it",
+				enum.Package, enum.Name, c.Name)
+		case values == ValuesNames:
+			result = append(result, c.Text)
+		default:
+			v, err := Value(typ, c.Value)
+			if err != nil {
+				return "", nil, fmt.Errorf("%s.%s: constant %s: %w", enum.Package, enum.Name, c.Name, err)
+			}
+			result = append(result, v)
+		}
+	}
+	return typ, result, nil
+}
+
+// Distinct returns the constants of an enum with distinct values, in
+// declaration order.
+func Distinct(enum analyzer.CatalogEnum) []analyzer.CatalogConstant {
+	var result []analyzer.CatalogConstant
+	seen := make(map[string]bool)
+	for _, c := range enum.Constants {
+		if !seen[c.Value] {
+			seen[c.Value] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// jsonType returns the JSON Schema type of the values of an underlying type.
+func jsonType(underlying string) (string, error) {
+	switch underlying {
+	case "string":
+		return "string", nil
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		return "integer", nil
+	case "float32", "float64":
+		return "number", nil
+	default:
+		return "", fmt.Errorf("unsupported underlying type %s", underlying)
+	}
+}
+
+// Value converts the exact Go representation of a constant value to a
+// string or a JSON number, depending on the JSON type.
+func Value(jsonType, exact string) (any, error) {
+	v := parseValue(exact)
+	switch {
+	case v.Kind() == constant.Unknown:
+		return nil, fmt.Errorf("invalid value %s", exact)
+	case jsonType == "string":
+		return constant.StringVal(v), nil
+	case jsonType == "integer":
+		return json.Number(exact), nil
+	}
+	f, _ := constant.Float64Val(v)
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+}
+
+// parseValue parses the exact Go representation of a constant value.
+// Floating-point values may be exact fractions, such as "1/3".
+func parseValue(exact string) constant.Value {
+	if strings.HasPrefix(exact, `"`) {
+		return constant.MakeFromLiteral(exact, token.STRING, 0)
+	}
+	if num, denom, ok := strings.Cut(exact, "/"); ok {
+		return constant.BinaryOp(constant.MakeFromLiteral(num, token.INT, 0), token.QUO, constant.MakeFromLiteral(denom, token.INT, 0))
+	}
+	if v := constant.MakeFromLiteral(exact, token.INT, 0); v.Kind() != constant.Unknown {
+		return v
+	}
+	return constant.MakeFromLiteral(exact, token.FLOAT, 0)
+}
+
+// SortedByValue returns the constants of an enum with distinct values,
+// ordered by value.
+func SortedByValue(enum analyzer.CatalogEnum) []analyzer.CatalogConstant {
+	constants := Distinct(enum)
+	slices.SortStableFunc(constants, func(a, b analyzer.CatalogConstant) int {
+		x, y := parseValue(a.Value), parseValue(b.Value)
+		switch {
+		case x.Kind() == constant.Unknown || y.Kind() == constant.Unknown:
+			return strings.Compare(a.Value, b.Value)
+		case constant.Compare(x, token.LSS, y):
+			return -1
+		case constant.Compare(x, token.GTR, y):
+			return 1
+		default:
+			return 0
+		}
+	})
+	return constants
+}
+
+// memberName returns the name of a constant in TypeScript and proto files.
+func memberName(enum analyzer.CatalogEnum, constant string, opts Options, defaultCase string) string {
+	prefix, c := "", opts.Case
+	if opts.TrimPrefix {
+		prefix = enum.Name
+	}
+	if c == "" {
+		c = defaultCase
+	}
+	return enumgen.Name(constant, prefix, c)
+}
+
+// typeNames returns the names of the enums of the catalog in TypeScript and
+// proto files: the type name, prefixed by the capitalized last element of
+// the package path when several packages declare the same name.
+func typeNames(catalog *analyzer.Catalog) ([]string, error) {
+	count := make(map[string]int)
+	for _, enum := range catalog.Enums {
+		count[enum.Name]++
+	}
+
+	names := make([]string, len(catalog.Enums))
+	seen := make(map[string]bool)
+	for i, enum := range catalog.Enums {
+		name := enum.Name
+		if count[name] > 1 {
+			prefix := strings.Map(func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, path.Base(enum.Package))
+			if prefix != "" {
+				name = strings.ToUpper(prefix[:1]) + prefix[1:] + name
+			}
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s.%s: type name %s is already used", enum.Package, enum.Name, name)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names, nil
+}
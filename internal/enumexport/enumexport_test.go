@@ -0,0 +1,258 @@
+package enumexport
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+func TestNewSchema(t *testing.T) {
+	status := analyzer.CatalogEnum{Package: "example.com/p", Name: "Status", Underlying: "uint8", Doc: "Status of a job.",
+		Constants: []analyzer.CatalogConstant{
+			{Name: "StatusActive", Value: "0", Text: "active", Doc: "Running."},
+			{Name: "StatusDone", Value: "1", Text: "done"},
+			{Name: "StatusDefault", Value: "0", Text: "active"},
+		}}
+
+	tests := []struct {
+		values string
+		want   Schema
+	}{
+		{ValuesAuto, Schema{Type: "string", Description: "Status of a job.", Enum: []any{"active", "done"},
+			VarNames: []string{"StatusActive", "StatusDone"}, Descriptions: []string{"Running.", ""}}},
+		{ValuesValues, Schema{Type: "integer", Description: "Status of a job.", Enum: []any{json.Number("0"), json.Number("1")},
+			VarNames: []string{"StatusActive", "StatusDone"}, Descriptions: []string{"Running.", ""}}},
+	}
+	for _, tt := range tests {
+		got, err := NewSchema(status, tt.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewSchema(%s) = %+v, want %+v", tt.values, got, tt.want)
+		}
+	}
+
+	// Without names, auto falls back to values and names fails
+	color := analyzer.CatalogEnum{Package: "example.com/p", Name: "Color", Underlying: "string",
+		Constants: []analyzer.CatalogConstant{{Name: "ColorRed", Value: `"red"`}, {Name: "ColorBlue", Value: `"blue"`, Text: "Blue"}}}
+	got, err := NewSchema(color, ValuesAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{"red", "blue"}; got.Type != "string" || !reflect.DeepEqual(got.Enum, want) || got.Descriptions != nil {
+		t.Errorf("NewSchema(auto) = %+v, want string values %v", got, want)
+	}
+	if _, err := NewSchema(color, ValuesNames); err == nil || !strings.Contains(err.Error(), "ColorRed has no name") {
+		t.Errorf("NewSchema(names) error = %v, want missing name of ColorRed", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		jsonType, exact string
+		want            any
+	}{
+		{"string", `"in\tprogress"`, "in\tprogress"},
+		{"integer", "-3", json.Number("-3")},
+		{"number", "0.5", json.Number("0.5")},
+		{"number", "1/4", json.Number("0.25")},
+	}
+	for _, tt := range tests {
+		got, err := Value(tt.jsonType, tt.exact)
+		if err != nil || got != tt.want {
+			t.Errorf("Value(%s, %s) = %v, %v, want %v", tt.jsonType, tt.exact, got, err, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	catalog := &analyzer.Catalog{Enums: []analyzer.CatalogEnum{
+		{Package: "example.com/a", Name: "Kind", Underlying: "int", Constants: []analyzer.CatalogConstant{{Name: "KindA", Value: "1"}}},
+		{Package: "example.com/b", Name: "Kind", Underlying: "int", Constants: []analyzer.CatalogConstant{{Name: "KindB", Value: "2"}}},
+		{Package: "example.com/b", Name: "Mode", Underlying: "int", Constants: []analyzer.CatalogConstant{{Name: "ModeX", Value: "3"}}},
+	}}
+
+	jsonSchema, err := Render(catalog, Options{Format: FormatJSONSchema})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Schema string                     `json:"$schema"`
+		Defs   map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(jsonSchema, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Schema != JSONSchemaDialect || len(doc.Defs) != 3 ||
+		doc.Defs["example.com.a.Kind"] == nil || doc.Defs["example.com.b.Kind"] == nil || doc.Defs["Mode"] == nil {
+		t.Errorf("Render(jsonschema) =\n%s", jsonSchema)
+	}
+
+	openAPI, err := Render(catalog, Options{Format: FormatOpenAPI, Values: ValuesValues})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"Mode": {
+        "type": "integer",
+        "enum": [
+          3
+        ],
+        "x-enum-varnames": [
+          "ModeX"
+        ]
+      }`
+	if !strings.HasPrefix(string(openAPI), "{\n  \"components\": {\n    \"schemas\": {") || !strings.Contains(string(openAPI), want) {
+		t.Errorf("Render(openapi) =\n%s\nwant it to contain\n%s", openAPI, want)
+	}
+
+	if _, err := Render(catalog, Options{Format: "yaml"}); err == nil {
+		t.Error("Render() accepted format yaml")
+	}
+}
+
+func TestSortedByValue(t *testing.T) {
+	enum := analyzer.CatalogEnum{Constants: []analyzer.CatalogConstant{
+		{Name: "C", Value: "10"}, {Name: "A", Value: "-1"}, {Name: "B", Value: "2"}, {Name: "D", Value: "-1"},
+	}}
+	var got []string
+	for _, c := range SortedByValue(enum) {
+		got = append(got, c.Name)
+	}
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedByValue() = %v, want %v", got, want)
+	}
+}
+
+func TestRenderTypeScript(t *testing.T) {
+	catalog := &analyzer.Catalog{Enums: []analyzer.CatalogEnum{
+		{Package: "example.com/p", Name: "Status", Underlying: "uint8", Doc: "Status of a job.", Constants: []analyzer.CatalogConstant{
+			{Name: "StatusDone", Value: "2", Text: "done"},
+			{Name: "StatusActive", Value: "1", Text: "active", Doc: "Running."},
+		}},
+		{Package: "example.com/p", Name: "Mode", Underlying: "string", Constants: []analyzer.CatalogConstant{
+			{Name: "ModeWrite", Value: `"w"`}, {Name: "ModeReadOnly", Value: `"r"`},
+		}},
+	}}
+
+	got, err := Render(catalog, Options{Format: FormatTypeScript, TrimPrefix: true, Case: "kebab"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Header + `
+
+/** Status of a job. */
+export enum Status {
+  /** Running. */
+  active = "active",
+  done = "done",
+}
+
+export enum Mode {
+  "read-only" = "r",
+  write = "w",
+}
+`
+	if string(got) != want {
+		t.Errorf("Render(typescript) =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = Render(catalog, Options{Format: FormatTypeScript, TSStyle: TSUnion, Values: ValuesValues})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "export type Status = 1 | 2;\n\nexport type Mode = \"r\" | \"w\";\n"; !strings.HasSuffix(string(got), want) {
+		t.Errorf("Render(typescript union) =\n%s\nwant suffix\n%s", got, want)
+	}
+}
+
+func TestRenderProto(t *testing.T) {
+	catalog := &analyzer.Catalog{Enums: []analyzer.CatalogEnum{
+		{Package: "example.com/p", Name: "Status", Underlying: "int", Doc: "Status of a job.", Constants: []analyzer.CatalogConstant{
+			{Name: "StatusDone", Value: "2"}, {Name: "StatusFailed", Value: "-1", Doc: "Failed."}, {Name: "StatusActive", Value: "1"},
+		}},
+		{Package: "example.com/p", Name: "Level", Underlying: "uint8", Constants: []analyzer.CatalogConstant{
+			{Name: "LevelHigh", Value: "1"}, {Name: "LevelLow", Value: "0"},
+		}},
+	}}
+
+	got, err := Render(catalog, Options{Format: FormatProto, ProtoPackage: "jobs.v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Header + `
+
+syntax = "proto3";
+
+package jobs.v1;
+
+// Status of a job.
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  // Failed.
+  STATUS_FAILED = -1;
+  STATUS_ACTIVE = 1;
+  STATUS_DONE = 2;
+}
+
+enum Level {
+  LEVEL_LOW = 0;
+  LEVEL_HIGH = 1;
+}
+`
+	if string(got) != want {
+		t.Errorf("Render(proto) =\n%s\nwant\n%s", got, want)
+	}
+
+	stringEnum := &analyzer.Catalog{Enums: []analyzer.CatalogEnum{{Package: "example.com/p", Name: "Mode", Underlying: "string"}}}
+	if _, err := Render(stringEnum, Options{Format: FormatProto}); err == nil {
+		t.Error("Render(proto) accepted a string enum")
+	}
+	if _, err := Render(catalog, Options{Format: FormatProto, Case: "kebab"}); err == nil {
+		t.Error("Render(proto) accepted kebab-case value names")
+	}
+}
+
+func TestRenderProtoTrimPrefix(t *testing.T) {
+	catalog := &analyzer.Catalog{Enums: []analyzer.CatalogEnum{
+		{Package: "example.com/p", Name: "Status", Underlying: "int", Constants: []analyzer.CatalogConstant{
+			{Name: "StatusActive", Value: "1"}, {Name: "StatusDone", Value: "2"},
+		}},
+		{Package: "example.com/p", Name: "Level", Underlying: "int", Constants: []analyzer.CatalogConstant{
+			{Name: "LevelHigh", Value: "1"},
+		}},
+	}}
+
+	got, err := Render(catalog, Options{Format: FormatProto, TrimPrefix: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Header + `
+
+syntax = "proto3";
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  ACTIVE = 1;
+  DONE = 2;
+}
+
+enum Level {
+  LEVEL_UNSPECIFIED = 0;
+  HIGH = 1;
+}
+`
+	if string(got) != want {
+		t.Errorf("Render(proto) =\n%s\nwant\n%s", got, want)
+	}
+
+	// Proto enum values share the package scope
+	catalog.Enums[1].Constants = append(catalog.Enums[1].Constants, analyzer.CatalogConstant{Name: "LevelActive", Value: "2"})
+	_, err = Render(catalog, Options{Format: FormatProto, TrimPrefix: true})
+	if err == nil || !strings.Contains(err.Error(), `"ACTIVE" is already used by Status`) {
+		t.Errorf("Render(proto) error = %v, want ACTIVE already used by Status", err)
+	}
+}
@@ -0,0 +1,89 @@
+package enumexport
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"math"
+	"regexp"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+// protoIdent matches the identifiers allowed as proto enum value names.
+var protoIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// renderProto returns a proto3 file declaring an enum for every enum of the
+// catalog. Values are ordered by value, except that proto3 requires the
+// zero value first: enums without a zero constant get a <TYPE>_UNSPECIFIED
+// value, which keeps the type prefix even with TrimPrefix. As proto enum
+// values share the scope of the package, their names must be unique across
+// the file. Only enums with integer values in the int32 range can be exported.
+func renderProto(catalog *analyzer.Catalog, opts Options) ([]byte, error) {
+	names, err := typeNames(catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(Header + "\n\nsyntax = \"proto3\";\n")
+	if opts.ProtoPackage != "" {
+		fmt.Fprintf(&buf, "\npackage %s;\n", opts.ProtoPackage)
+	}
+	seen := make(map[string]string) // value name to the enum declaring it
+	for i, enum := range catalog.Enums {
+		if typ, err := jsonType(enum.Underlying); err != nil || typ != "integer" {
+			return nil, fmt.Errorf("%s.%s: proto enums need integer values, not %s", enum.Package, enum.Name, enum.Underlying)
+		}
+
+		type member struct {
+			name, doc string
+			value     int64
+		}
+		var members []member
+		hasZero := false
+		for _, c := range SortedByValue(enum) {
+			v, ok := constant.Int64Val(parseValue(c.Value))
+			if !ok || v < math.MinInt32 || v > math.MaxInt32 {
+				return nil, fmt.Errorf("%s.%s: constant %s: value %s is out of the int32 range", enum.Package, enum.Name, c.Name, c.Value)
+			}
+			m := member{memberName(enum, c.Name, opts, "upper-snake"), c.Doc, v}
+			if v == 0 {
+				// The zero value goes first
+				hasZero = true
+				members = append([]member{m}, members...)
+			} else {
+				members = append(members, m)
+			}
+		}
+		if !hasZero {
+			prefixed := opts
+			prefixed.TrimPrefix = false
+			members = append([]member{{name: memberName(enum, enum.Name+"Unspecified", prefixed, "upper-snake")}}, members...)
+		}
+
+		fmt.Fprintf(&buf, "\n")
+		writeProtoDoc(&buf, "", enum.Doc)
+		fmt.Fprintf(&buf, "enum %s {\n", names[i])
+		for _, m := range members {
+			if !protoIdent.MatchString(m.name) {
+				return nil, fmt.Errorf("%s.%s: %q is not a valid proto enum value name", enum.Package, enum.Name, m.name)
+			}
+			if other, ok := seen[m.name]; ok {
+				return nil, fmt.Errorf("%s.%s: proto enum value name %q is already used by %s", enum.Package, enum.Name, m.name, other)
+			}
+			seen[m.name] = names[i]
+			writeProtoDoc(&buf, "  ", m.doc)
+			fmt.Fprintf(&buf, "  %s = %d;\n", m.name, m.value)
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// writeProtoDoc writes a doc comment, if any, with the given indentation.
+func writeProtoDoc(buf *bytes.Buffer, indent, doc string) {
+	if doc != "" {
+		fmt.Fprintf(buf, "%s// %s\n", indent, doc)
+	}
+}
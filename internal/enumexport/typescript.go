@@ -0,0 +1,77 @@
+package enumexport
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+// renderTypeScript returns a TypeScript module exporting an enum, or a union
+// of literal types, for every enum of the catalog. Members are ordered by
+// value.
+func renderTypeScript(catalog *analyzer.Catalog, opts Options) ([]byte, error) {
+	if opts.TSStyle != "" && opts.TSStyle != TSEnum && opts.TSStyle != TSUnion {
+		return nil, fmt.Errorf("unknown TypeScript style %q", opts.TSStyle)
+	}
+	names, err := typeNames(catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(Header + "\n")
+	for i, enum := range catalog.Enums {
+		constants := SortedByValue(enum)
+		_, values, err := enumValues(enum, constants, opts.Values)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteString("\n")
+		writeJSDoc(&buf, "", enum.Doc)
+		if opts.TSStyle == TSUnion {
+			literals := make([]string, len(values))
+			for j, v := range values {
+				literals[j] = tsLiteral(v)
+			}
+			fmt.Fprintf(&buf, "export type %s = %s;\n", names[i], strings.Join(literals, " | "))
+			continue
+		}
+
+		fmt.Fprintf(&buf, "export enum %s {\n", names[i])
+		seen := make(map[string]bool)
+		for j, c := range constants {
+			member := memberName(enum, c.Name, opts, "")
+			if seen[member] {
+				return nil, fmt.Errorf("%s.%s: constant %s is renamed to %s, which is already used", enum.Package, enum.Name, c.Name, member)
+			}
+			seen[member] = true
+			if !token.IsIdentifier(member) {
+				member = strconv.Quote(member)
+			}
+			writeJSDoc(&buf, "  ", c.Doc)
+			fmt.Fprintf(&buf, "  %s = %s,\n", member, tsLiteral(values[j]))
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// tsLiteral returns the TypeScript literal of a string or a json.Number.
+func tsLiteral(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// writeJSDoc writes a doc comment, if any, with the given indentation.
+func writeJSDoc(buf *bytes.Buffer, indent, doc string) {
+	if doc != "" {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, strings.ReplaceAll(doc, "*/", "*\\/"))
+	}
+}
@@ -0,0 +1,312 @@
+// Package enumgen renders helper methods for quasi-enum types described by
+// the enumsafety catalog, so that generated code agrees with the analyzer on
+// what an enum is and which constants it has.
+package enumgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+	"github.com/Djarvur/go-enumsafety/internal/helpersrc"
+)
+
+// DirectivePrefix starts the comments configuring the generation of a type,
+// e.g. "//enumgen:sql case=snake".
+const DirectivePrefix = "//enumgen:"
+
+// Header is the first line of the files written by Generate.
+const Header = "// Code generated by enumgen; DO NOT EDIT."
+
+// Options configure the code generated for one quasi-enum type.
+type Options struct {
+	SQL        bool            // generate Scan and Value (sql.Scanner, driver.Valuer)
+	TrimPrefix string          // prefix removed from constant names to form their names
+	Case       string          // "", "lower", "upper", "snake", "kebab" or "upper-snake"
+	Skip       bool            // generate nothing for the type
+	Omit       map[string]bool // methods and functions the type already has
+}
+
+// ParseDirectives applies the //enumgen: directives of the comment groups to
+// opts. Each directive line holds space-separated options: sql, skip,
+// trimprefix (the type name) or trimprefix=Prefix, and
+// case=lower|upper|snake|kebab|upper-snake.
+func ParseDirectives(typeName string, opts Options, groups ...*ast.CommentGroup) (Options, error) {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			rest, ok := strings.CutPrefix(comment.Text, DirectivePrefix)
+			if !ok {
+				continue
+			}
+			for _, field := range strings.Fields(rest) {
+				key, value, hasValue := strings.Cut(field, "=")
+				switch {
+				case key == "sql" && !hasValue:
+					opts.SQL = true
+				case key == "skip" && !hasValue:
+					opts.Skip = true
+				case key == "trimprefix" && !hasValue:
+					opts.TrimPrefix = typeName
+				case key == "trimprefix":
+					opts.TrimPrefix = value
+				case key == "case" && ValidCase(value):
+					opts.Case = value
+				default:
+					return opts, fmt.Errorf("%s: invalid directive %q", typeName, field)
+				}
+			}
+		}
+	}
+	return opts, nil
+}
+
+// ValidCase reports whether c names a supported case transformation.
+func ValidCase(c string) bool {
+	switch c {
+	case "", "lower", "upper", "snake", "kebab", "upper-snake":
+		return true
+	default:
+		return false
+	}
+}
+
+// Name returns the text form of a constant: its name without the prefix and
+// the underscores following it (unless that leaves nothing), transformed to
+// the requested case.
+func Name(constant, trimPrefix, c string) string {
+	name := constant
+	if trimmed := strings.TrimLeft(strings.TrimPrefix(name, trimPrefix), "_"); trimPrefix != "" && trimmed != "" {
+		name = trimmed
+	}
+	switch c {
+	case "lower":
+		return strings.ToLower(name)
+	case "upper":
+		return strings.ToUpper(name)
+	case "snake":
+		return strings.Join(words(name), "_")
+	case "kebab":
+		return strings.Join(words(name), "-")
+	case "upper-snake":
+		return strings.ToUpper(strings.Join(words(name), "_"))
+	default:
+		return name
+	}
+}
+
+// words splits a camel-case identifier into lowercase words:
+// "HTTPServerError" becomes "http", "server", "error".
+func words(name string) []string {
+	runes := []rune(name)
+	var result []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case cur == '_':
+			result = append(result, strings.ToLower(string(runes[start:i])))
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)),
+			unicode.IsUpper(cur) && unicode.IsUpper(prev) && unicode.IsLower(next):
+			result = append(result, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	result = append(result, strings.ToLower(string(runes[start:])))
+
+	// Drop empty words left by leading or doubled underscores
+	words := result[:0]
+	for _, w := range result {
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// ParseFunc returns the name of the function parsing the type: "ParseStatus"
+// for exported types, "parseStatus" for unexported ones.
+func ParseFunc(typeName string) string {
+	if ast.IsExported(typeName) {
+		return "Parse" + typeName
+	}
+	first, size := utf8.DecodeRuneInString(typeName)
+	return "parse" + string(unicode.ToUpper(first)) + typeName[size:]
+}
+
+// Generate renders the gofmt-formatted source of a file declaring the
+// helpers of an enum in package pkgName. Constants sharing a value are
+// printed by their first name; every name is accepted by the parser.
+func Generate(pkgName string, enum analyzer.CatalogEnum, opts Options) ([]byte, error) {
+	g := &generator{
+		enum:    enum,
+		opts:    opts,
+		recv:    helpersrc.ReceiverName(enum.Name, constantNames(enum.Constants)),
+		imports: make(map[string]bool),
+	}
+	g.render()
+
+	var src bytes.Buffer
+	src.WriteString(Header + "\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, strconv.Quote(path))
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&src, "import (\n%s\n)\n", strings.Join(paths, "\n"))
+	}
+	src.Write(g.body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: formatting generated code: %w", enum.Name, err)
+	}
+	return formatted, nil
+}
+
+// generator accumulates the methods generated for one enum.
+type generator struct {
+	enum analyzer.CatalogEnum
+	opts Options
+	recv string
+	body bytes.Buffer
+
+	// Paths of the packages referred to by the body
+	imports map[string]bool
+}
+
+// printf appends formatted source to the body.
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+// use records that the body refers to the package with the given path.
+func (g *generator) use(path string) {
+	g.imports[path] = true
+}
+
+// render appends every method not omitted by the options.
+func (g *generator) render() {
+	typeName, recv := g.enum.Name, g.recv
+	parse := ParseFunc(typeName)
+	distinct := g.distinct()
+
+	// rawValue prints the value of the receiver without calling String
+	rawVerb, rawValue := "%v", fmt.Sprintf("%s(%s)", g.enum.Underlying, recv)
+	if g.enum.Underlying == "string" {
+		rawVerb = "%q"
+	}
+
+	if !g.opts.Omit["String"] {
+		g.use("fmt")
+		g.printf("\n// String returns the name of %s, or %s(value) for values outside the enum.\n", recv, typeName)
+		g.printf("func (%s %s) String() string {\n\tswitch %s {\n", recv, typeName, recv)
+		for _, c := range distinct {
+			g.printf("\tcase %s:\n\t\treturn %q\n", c.Name, g.name(c.Name))
+		}
+		g.printf("\t}\n\treturn fmt.Sprintf(\"%s(%s)\", %s)\n}\n", typeName, rawVerb, rawValue)
+	}
+
+	if !g.opts.Omit[parse] {
+		g.use("fmt")
+		g.printf("\n// %s returns the %s constant with the given name.\n", parse, typeName)
+		g.printf("func %s(name string) (%s, error) {\n\tswitch name {\n", parse, typeName)
+		seen := make(map[string]bool)
+		for _, c := range g.enum.Constants {
+			name := g.name(c.Name)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			g.printf("\tcase %q:\n\t\treturn %s, nil\n", name, c.Name)
+		}
+		g.printf("\t}\n\tvar zero %s\n\treturn zero, fmt.Errorf(\"invalid %s name %%q\", name)\n}\n", typeName, typeName)
+	}
+
+	// MarshalText does not call String: a String declared by the type may
+	// print names that the generated parser does not accept
+	if !g.opts.Omit["MarshalText"] {
+		g.use("fmt")
+		g.printf("\n// MarshalText implements encoding.TextMarshaler, using the names accepted by %s.\n", parse)
+		g.printf("func (%s %s) MarshalText() ([]byte, error) {\n\tswitch %s {\n", recv, typeName, recv)
+		for _, c := range distinct {
+			g.printf("\tcase %s:\n\t\treturn []byte(%q), nil\n", c.Name, g.name(c.Name))
+		}
+		g.printf("\t}\n\treturn nil, fmt.Errorf(\"invalid %s value %s\", %s)\n}\n", typeName, rawVerb, rawValue)
+	}
+
+	if !g.opts.Omit["UnmarshalText"] {
+		g.printf("\n// UnmarshalText implements encoding.TextUnmarshaler.\n")
+		g.printf("func (%s *%s) UnmarshalText(text []byte) error {\n", recv, typeName)
+		g.printf("\tv, err := %s(string(text))\n\tif err != nil {\n\t\treturn err\n\t}\n\t*%s = v\n\treturn nil\n}\n", parse, recv)
+	}
+
+	if !g.opts.Omit["IsValid"] {
+		g.printf("\n%s\n", helpersrc.IsValid(typeName, recv, constantNames(distinct)))
+	}
+
+	if !g.opts.Omit["Values"] {
+		g.printf("\n%s\n", helpersrc.Values(typeName, constantNames(distinct)))
+	}
+
+	if !g.opts.SQL {
+		return
+	}
+	if !g.opts.Omit["Scan"] {
+		g.use("fmt")
+		g.printf("\n// Scan implements sql.Scanner, reading the name of a constant.\n")
+		g.printf("func (%s *%s) Scan(src any) error {\n\tswitch v := src.(type) {\n", recv, typeName)
+		g.printf("\tcase string:\n\t\treturn %s.UnmarshalText([]byte(v))\n", recv)
+		g.printf("\tcase []byte:\n\t\treturn %s.UnmarshalText(v)\n\t}\n", recv)
+		g.printf("\treturn fmt.Errorf(\"cannot scan %%T into %s\", src)\n}\n", typeName)
+	}
+	if !g.opts.Omit["Value"] {
+		g.use("database/sql/driver")
+		g.printf("\n// Value implements driver.Valuer, storing the name of the constant.\n")
+		g.printf("func (%s %s) Value() (driver.Value, error) {\n", recv, typeName)
+		g.printf("\ttext, err := %s.MarshalText()\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn string(text), nil\n}\n", recv)
+	}
+}
+
+// name returns the text form of a constant.
+func (g *generator) name(constant string) string {
+	return Name(constant, g.opts.TrimPrefix, g.opts.Case)
+}
+
+// distinct returns the constants with distinct values, in declaration order.
+func (g *generator) distinct() []analyzer.CatalogConstant {
+	var result []analyzer.CatalogConstant
+	seen := make(map[string]bool)
+	for _, c := range g.enum.Constants {
+		if !seen[c.Value] {
+			seen[c.Value] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// constantNames returns the names of the constants.
+func constantNames(constants []analyzer.CatalogConstant) []string {
+	names := make([]string, len(constants))
+	for i, c := range constants {
+		names[i] = c.Name
+	}
+	return names
+}
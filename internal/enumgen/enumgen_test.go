@@ -0,0 +1,184 @@
+package enumgen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/Djarvur/go-enumsafety/analyzer"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		constant, prefix, c, want string
+	}{
+		{"StatusActive", "", "", "StatusActive"},
+		{"StatusActive", "Status", "", "Active"},
+		{"StatusInProgress", "Status", "snake", "in_progress"},
+		{"StatusInProgress", "Status", "kebab", "in-progress"},
+		{"HTTPServerError", "", "snake", "http_server_error"},
+		{"Level2Fast", "", "snake", "level2_fast"},
+		{"Status_Done", "Status", "snake", "done"},
+		{"StatusActive", "Status", "upper", "ACTIVE"},
+		{"StatusInProgress", "", "upper-snake", "STATUS_IN_PROGRESS"},
+		{"Status", "Status", "lower", "status"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.constant, tt.prefix, tt.c); got != tt.want {
+			t.Errorf("Name(%q, %q, %q) = %q, want %q", tt.constant, tt.prefix, tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestParseFunc(t *testing.T) {
+	tests := []struct{ typeName, want string }{
+		{"Status", "ParseStatus"},
+		{"status", "parseStatus"},
+		{"über", "parseÜber"},
+	}
+	for _, tt := range tests {
+		if got := ParseFunc(tt.typeName); got != tt.want {
+			t.Errorf("ParseFunc(%q) = %q, want %q", tt.typeName, got, tt.want)
+		}
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	group := &ast.CommentGroup{List: []*ast.Comment{
+		{Text: "// Status enum"},
+		{Text: "//enumgen:sql trimprefix"},
+		{Text: "//enumgen:case=kebab"},
+	}}
+	opts, err := ParseDirectives("Status", Options{Case: "lower"}, nil, group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.SQL || opts.TrimPrefix != "Status" || opts.Case != "kebab" || opts.Skip {
+		t.Errorf("ParseDirectives() = %+v", opts)
+	}
+
+	bad := &ast.CommentGroup{List: []*ast.Comment{{Text: "//enumgen:case=title"}}}
+	if _, err := ParseDirectives("Status", Options{}, bad); err == nil {
+		t.Error("ParseDirectives() accepted case=title")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	const decl = `package p
+
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInProgress
+	StatusDefault = StatusActive
+)
+
+type Color string
+
+const (
+	ColorRed   Color = "red"
+	ColorGreen Color = "green"
+)
+
+func (c Color) String() string { return string(c) }
+`
+	status := analyzer.CatalogEnum{Package: "p", Name: "Status", Underlying: "uint8", Constants: []analyzer.CatalogConstant{
+		{Name: "StatusActive", Value: "0"}, {Name: "StatusInProgress", Value: "1"}, {Name: "StatusDefault", Value: "0"},
+	}}
+	color := analyzer.CatalogEnum{Package: "p", Name: "Color", Underlying: "string", Constants: []analyzer.CatalogConstant{
+		{Name: "ColorRed", Value: `"red"`}, {Name: "ColorGreen", Value: `"green"`},
+	}}
+
+	statusSrc, err := Generate("p", status, Options{SQL: true, TrimPrefix: "Status", Case: "snake"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	colorSrc, err := Generate("p", color, Options{Omit: map[string]bool{"String": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"// Code generated by enumgen; DO NOT EDIT.",
+		`"database/sql/driver"`,
+		"case StatusActive:\n\t\treturn \"active\"",
+		"case \"in_progress\":\n\t\treturn StatusInProgress, nil",
+		"case \"default\":\n\t\treturn StatusDefault, nil",
+		"case StatusActive, StatusInProgress:\n\t\treturn true",
+		"return []Status{StatusActive, StatusInProgress}",
+		"func (s *Status) Scan(src any) error",
+	} {
+		if !strings.Contains(string(statusSrc), want) {
+			t.Errorf("generated Status code lacks %q:\n%s", want, statusSrc)
+		}
+	}
+	if strings.Contains(string(colorSrc), "String() string") || strings.Contains(string(colorSrc), "Scan(") {
+		t.Errorf("generated Color code has omitted methods:\n%s", colorSrc)
+	}
+	// Color declares String: MarshalText must print the names ParseColor accepts
+	for _, want := range []string{
+		"case ColorRed:\n\t\treturn []byte(\"ColorRed\"), nil",
+		"case \"ColorRed\":\n\t\treturn ColorRed, nil",
+	} {
+		if !strings.Contains(string(colorSrc), want) {
+			t.Errorf("generated Color code lacks %q:\n%s", want, colorSrc)
+		}
+	}
+	if strings.Contains(string(colorSrc), ".String()") {
+		t.Errorf("generated Color code calls String:\n%s", colorSrc)
+	}
+
+	// The generated files must compile together with the declarations
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"decl.go": decl, "status_enum.go": string(statusSrc), "color_enum.go": string(colorSrc)} {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("p", fset, files, nil); err != nil {
+		t.Errorf("generated code does not compile: %v", err)
+	}
+}
+
+func TestGenerateImports(t *testing.T) {
+	status := analyzer.CatalogEnum{Package: "p", Name: "Status", Underlying: "uint8", Constants: []analyzer.CatalogConstant{
+		{Name: "StatusActive", Value: "0"}, {Name: "StatusInactive", Value: "1"},
+	}}
+	omitFmt := map[string]bool{"String": true, "ParseStatus": true, "MarshalText": true, "Scan": true}
+
+	tests := []struct {
+		opts Options
+		want []string
+	}{
+		{Options{}, []string{`"fmt"`}},
+		{Options{SQL: true}, []string{`"database/sql/driver"`, `"fmt"`}},
+		{Options{Omit: omitFmt}, nil},
+		{Options{SQL: true, Omit: omitFmt}, []string{`"database/sql/driver"`}},
+	}
+	for _, tt := range tests {
+		src, err := Generate("p", status, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "status_enum.go", src, parser.ImportsOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, spec := range f.Imports {
+			got = append(got, spec.Path.Value)
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("Generate(%+v) imports %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
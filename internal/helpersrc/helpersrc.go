@@ -0,0 +1,43 @@
+// Package helpersrc renders the value helper methods that both the
+// analyzer's suggested fixes and enumgen generate, so that the two agree on
+// their names, receivers and bodies.
+package helpersrc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReceiverName returns the receiver of the helper methods of a type: the
+// lowercased first letter of its name, or "x" if a constant, or the loop
+// variable v of the generated code, is named so.
+func ReceiverName(typeName string, constants []string) string {
+	first, _ := utf8.DecodeRuneInString(typeName)
+	name := string(unicode.ToLower(first))
+	if name == "v" {
+		return "x"
+	}
+	for _, c := range constants {
+		if c == name {
+			return "x"
+		}
+	}
+	return name
+}
+
+// IsValid returns the source of an IsValid method accepting the constants,
+// with its doc comment and without a trailing newline.
+func IsValid(typeName, recv string, constants []string) string {
+	return fmt.Sprintf("// IsValid reports whether %s is one of the %s constants.\n", recv, typeName) +
+		fmt.Sprintf("func (%s %s) IsValid() bool {\n\tswitch %s {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}",
+			recv, typeName, recv, strings.Join(constants, ", "))
+}
+
+// Values returns the source of a Values method listing the constants, with
+// its doc comment and without a trailing newline.
+func Values(typeName string, constants []string) string {
+	return fmt.Sprintf("// Values returns the %s constants in declaration order.\n", typeName) +
+		fmt.Sprintf("func (%s) Values() []%s {\n\treturn []%s{%s}\n}", typeName, typeName, typeName, strings.Join(constants, ", "))
+}
@@ -1,7 +1,7 @@
 package a
 
 // Status enum
-type Status int // want "quasi-enum type Status uses int but has only 3 constants; consider using uint8 for memory optimization" "quasi-enum type Status lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type Status int // want "quasi-enum type Status uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Status lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
 
 const (
 	StatusActive Status = iota
@@ -38,7 +38,7 @@ func testLiteralArgument() {
 	SetStatus(2) // want "literal value passed as quasi-enum type Status"
 }
 
-type Config struct {
+type Config struct { // want "struct Config takes 8 bytes but could take 1 with Status as uint8"
 	Status Status
 }
 
@@ -30,7 +30,7 @@ func testVariableConversion() {
 }
 
 // Test cross-enum conversion
-type Level uint8 // want "quasi-enum type Level lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Level lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type Level uint8 // want "quasi-enum type Level lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Level lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Level is detected only by its constants"
 
 const (
 	LevelLow  Level = 1
@@ -0,0 +1,19 @@
+package aliases
+
+import (
+	"aliases/facade"
+	"aliases/models"
+)
+
+// Test uses of re-exported quasi-enum constants
+
+func use() []models.Status {
+	var s models.Status = facade.StatusActive
+	s = facade.Default // want `untyped constant assigned to quasi-enum type Status`
+	s = facade.Current
+
+	var t facade.Status = facade.StatusInactive
+	t = facade.Invalid // want `untyped constant assigned to quasi-enum type Status`
+
+	return []models.Status{s, t}
+}
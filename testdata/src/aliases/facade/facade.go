@@ -0,0 +1,23 @@
+package facade
+
+import "aliases/models"
+
+// Test re-exported quasi-enum constants
+
+// Re-exports of the models constants
+const (
+	StatusActive   = models.StatusActive
+	StatusInactive = models.StatusInactive
+)
+
+// Default is not an alias: it only shares the value of StatusInactive.
+const Default models.Status = 1 // want `constant of quasi-enum type Status declared outside its package`
+
+// Current aliases an alias.
+const Current = StatusActive
+
+// Invalid is not an alias: no constant has its value.
+const Invalid models.Status = 9 // want `constant of quasi-enum type Status declared outside its package`
+
+// Status re-exports the quasi-enum type.
+type Status = models.Status
@@ -0,0 +1,21 @@
+package models
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusActive:
+		return "active"
+	case StatusInactive:
+		return "inactive"
+	}
+	return "unknown"
+}
+
+func (s *Status) UnmarshalText(text []byte) error { return nil }
@@ -0,0 +1,50 @@
+package catalog
+
+// Test the catalog: names produced by MarshalText, or String, and doc comments
+
+// Color enum
+// Color is the color of a widget.
+type Color uint8
+
+const (
+	// ColorRed is the default color.
+	ColorRed   Color = iota
+	ColorGreen       // green, as grass
+	ColorBlue
+)
+
+func (c Color) String() string {
+	switch c {
+	case ColorRed:
+		return "Red"
+	case ColorGreen:
+		return "Green"
+	case ColorBlue:
+		return "Blue"
+	}
+	return "Color(?)"
+}
+
+func (c Color) MarshalText() ([]byte, error) {
+	switch c {
+	case ColorRed:
+		return []byte("red"), nil
+	case ColorGreen:
+		return []byte("green"), nil
+	case ColorBlue:
+		return []byte("blue"), nil
+	}
+	return nil, nil
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "red":
+		*c = ColorRed
+	case "green":
+		*c = ColorGreen
+	case "blue":
+		*c = ColorBlue
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package compact_type
+
+// Test US4: smallest integer type fitting the constant values
+
+type (
+	// Mode enum
+	Mode int // want "quasi-enum type Mode uses int but all values fit in uint8; consider using it for memory optimization"
+	// Name is not an enum.
+	Name string
+)
+
+const (
+	ModeRead Mode = iota
+	ModeWrite
+)
+
+// Offset enum
+type Offset int // want "quasi-enum type Offset uses int but all values fit in int8; consider using it for memory optimization"
+
+const (
+	OffsetBack    Offset = -1
+	OffsetNone    Offset = 0
+	OffsetForward Offset = 1
+)
+
+// Code enum
+type Code int64 // want "quasi-enum type Code uses int64 but all values fit in uint16; consider using it for memory optimization"
+
+const (
+	CodeOK    Code = 200
+	CodeError Code = 1000
+)
+
+// Delta enum
+type Delta int // want "quasi-enum type Delta uses int but all values fit in int16; consider using it for memory optimization"
+
+const (
+	DeltaDown Delta = -1000
+	DeltaUp   Delta = 1000
+)
+
+// Small enum
+type Small int8 // int8 already fits; uint8 would not be smaller
+
+const (
+	SmallLow  Small = 0
+	SmallHigh Small = 1
+)
+
+// Wide enum
+type Wide int32 // uint32 would not be smaller
+
+const (
+	WideLow  Wide = 0
+	WideHigh Wide = 1 << 30
+)
+
+func (m Mode) String() string                { return "" }
+func (m *Mode) UnmarshalText([]byte) error   { return nil }
+func (o Offset) String() string              { return "" }
+func (o *Offset) UnmarshalText([]byte) error { return nil }
+func (c Code) String() string                { return "" }
+func (c *Code) UnmarshalText([]byte) error   { return nil }
+func (d Delta) String() string               { return "" }
+func (d *Delta) UnmarshalText([]byte) error  { return nil }
+func (s Small) String() string               { return "" }
+func (s *Small) UnmarshalText([]byte) error  { return nil }
+func (w Wide) String() string                { return "" }
+func (w *Wide) UnmarshalText([]byte) error   { return nil }
@@ -3,7 +3,7 @@ package composite
 // Test composite literal edge cases
 
 // Status enum
-type Status int // want "quasi-enum type Status uses int but has only 3 constants; consider using uint8 for memory optimization" "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type Status int // want "quasi-enum type Status uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	StatusActive Status = iota
@@ -12,7 +12,7 @@ const (
 )
 
 // Priority enum
-type Priority int // want "quasi-enum type Priority uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type Priority lacks a String\\(\\) method" "quasi-enum type Priority lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type Priority int // want "quasi-enum type Priority uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Priority lacks a String\\(\\) method" "quasi-enum type Priority lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	PriorityLow  Priority = 1
@@ -20,7 +20,7 @@ const (
 )
 
 // Simple config
-type Config struct {
+type Config struct { // want "struct Config takes 32 bytes but could take 24 with Status as uint8 and Priority as uint8 and its fields reordered"
 	Status   Status
 	Priority Priority
 	Name     string
@@ -62,7 +62,7 @@ func testCompositeLiteralEdgeCases() {
 }
 
 // Test struct with only enum fields
-type EnumOnly struct {
+type EnumOnly struct { // want "struct EnumOnly takes 16 bytes but could take 2 with Status as uint8 and Priority as uint8"
 	S Status
 	P Priority
 }
@@ -5,21 +5,21 @@ package constraints_full
 
 // Single constant enum
 // enum
-type SingleConstEnum int // want "quasi-enum type SingleConstEnum violates DC-001 \\(minimum 2 constants\\): must have at least 2 constants" "quasi-enum type SingleConstEnum uses int but has only 1 constants; consider using uint8 for memory optimization" "quasi-enum type SingleConstEnum lacks a String\\(\\) method" "quasi-enum type SingleConstEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type SingleConstEnum int // want "quasi-enum type SingleConstEnum violates DC-001 \\(minimum 2 constants\\): must have at least 2 constants" "quasi-enum type SingleConstEnum uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type SingleConstEnum lacks a String\\(\\) method" "quasi-enum type SingleConstEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const SingleConstEnumValue SingleConstEnum = 1
 
 // Constants in different blocks
 // enum
-type SplitBlockEnum int // want "quasi-enum type SplitBlockEnum uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type SplitBlockEnum lacks a String\\(\\) method" "quasi-enum type SplitBlockEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type SplitBlockEnum int // want "quasi-enum type SplitBlockEnum uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type SplitBlockEnum lacks a String\\(\\) method" "quasi-enum type SplitBlockEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const SplitBlockEnumFirst SplitBlockEnum = 1
 
-const SplitBlockEnumSecond SplitBlockEnum = 2
+const SplitBlockEnumSecond SplitBlockEnum = 2 // want "quasi-enum type SplitBlockEnum violates DC-002 \\(same const block\\): constant SplitBlockEnumSecond is declared outside the primary const block"
 
 // Mixed constant block
 // enum
-type MixedBlockEnum int // want "quasi-enum type MixedBlockEnum violates DC-004 \\(exclusive const block\\): const block must contain only constants of this type" "quasi-enum type MixedBlockEnum uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type MixedBlockEnum lacks a String\\(\\) method" "quasi-enum type MixedBlockEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type MixedBlockEnum int // want "quasi-enum type MixedBlockEnum violates DC-004 \\(exclusive const block\\): const block must contain only constants of this type" "quasi-enum type MixedBlockEnum uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type MixedBlockEnum lacks a String\\(\\) method" "quasi-enum type MixedBlockEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	MixedBlockEnumFirst  MixedBlockEnum = 1
@@ -29,7 +29,7 @@ const (
 
 // Type and constants far apart
 // enum
-type FarApartEnum int // want "quasi-enum type FarApartEnum violates DC-005 \\(proximity\\): type definition and const block must be adjacent" "quasi-enum type FarApartEnum uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type FarApartEnum lacks a String\\(\\) method" "quasi-enum type FarApartEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type FarApartEnum int // want "quasi-enum type FarApartEnum violates DC-005 \\(proximity\\): var spacer1 sits between type definition and const block" "quasi-enum type FarApartEnum uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type FarApartEnum lacks a String\\(\\) method" "quasi-enum type FarApartEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 var spacer1 int
 var spacer2 int
@@ -7,7 +7,7 @@ package detection_edge
 // that spans multiple lines
 // enum
 // and has the keyword in the middle
-type MultiLineComment int // want "quasi-enum type MultiLineComment uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type MultiLineComment lacks a String\\(\\) method" "quasi-enum type MultiLineComment lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type MultiLineComment int // want "quasi-enum type MultiLineComment uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type MultiLineComment lacks a String\\(\\) method" "quasi-enum type MultiLineComment lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	MultiLineCommentFirst  MultiLineComment = 1
@@ -16,7 +16,7 @@ const (
 
 // DT-004: Comment with enum keyword but not at the beginning
 // This type is an enum for testing
-type NotAtStart int // want "quasi-enum type NotAtStart uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type NotAtStart lacks a String\\(\\) method" "quasi-enum type NotAtStart lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type NotAtStart int // want "quasi-enum type NotAtStart uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type NotAtStart lacks a String\\(\\) method" "quasi-enum type NotAtStart lacks an UnmarshalText\\(\\[\\]byte\\) error method" "quasi-enum type NotAtStart is detected only by its constants"
 
 const (
 	NotAtStartFirst  NotAtStart = 1
@@ -25,7 +25,7 @@ const (
 
 // Should be detected: enum at the very start
 // enum - this is a valid enum
-type ValidPreceding int // want "quasi-enum type ValidPreceding uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type ValidPreceding lacks a String\\(\\) method" "quasi-enum type ValidPreceding lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type ValidPreceding int // want "quasi-enum type ValidPreceding uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type ValidPreceding lacks a String\\(\\) method" "quasi-enum type ValidPreceding lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	ValidPrecedingFirst  ValidPreceding = 1
@@ -38,7 +38,7 @@ type Package struct {
 }
 
 // enum
-type SelectorTest int // want "quasi-enum type SelectorTest uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type SelectorTest lacks a String\\(\\) method" "quasi-enum type SelectorTest lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type SelectorTest int // want "quasi-enum type SelectorTest uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type SelectorTest lacks a String\\(\\) method" "quasi-enum type SelectorTest lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	SelectorTestFirst  SelectorTest = 1
@@ -0,0 +1,55 @@
+package enum_marker
+
+// Test FR-047: explicit enum markers for types detected only by constants
+
+// Color is detected by its constants only.
+type Color uint8 // want `quasi-enum type Color is detected only by its constants; add a "// Color enum" comment to mark it explicitly`
+
+const (
+	ColorRed Color = iota
+	ColorGreen
+)
+
+type Shape uint8 // want `quasi-enum type Shape is detected only by its constants`
+
+const (
+	ShapeCircle Shape = iota
+	ShapeSquare
+)
+
+// Size enum
+type Size uint8
+
+const (
+	SizeSmall Size = iota
+	SizeLarge
+)
+
+// Marked on the spec of a grouped declaration
+type (
+	// Kind enum
+	Kind uint8
+	// Label is not an enum.
+	Label string
+)
+
+const (
+	KindA Kind = iota
+	KindB
+)
+
+func (c Color) String() string { return "color" }
+
+func (c *Color) UnmarshalText(text []byte) error { return nil }
+
+func (s Shape) String() string { return "shape" }
+
+func (s *Shape) UnmarshalText(text []byte) error { return nil }
+
+func (s Size) String() string { return "size" }
+
+func (s *Size) UnmarshalText(text []byte) error { return nil }
+
+func (k Kind) String() string { return "kind" }
+
+func (k *Kind) UnmarshalText(text []byte) error { return nil }
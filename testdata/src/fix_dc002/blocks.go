@@ -0,0 +1,44 @@
+package fix_dc002
+
+// Test DC-002 suggested fix: stray constants move into the primary const block
+
+// Status enum
+type Status uint8 // want "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+// StatusPending is declared in its own block.
+const StatusPending Status = 2 // want "quasi-enum type Status violates DC-002 \\(same const block\\): constant StatusPending is declared outside the primary const block"
+
+const (
+	// StatusArchived continues an iota sequence and must be materialized.
+	StatusArchived Status = iota + 3 // want "quasi-enum type Status violates DC-002 \\(same const block\\): constant StatusArchived is declared outside the primary const block"
+	StatusDeleted                    // want "quasi-enum type Status violates DC-002 \\(same const block\\): constant StatusDeleted is declared outside the primary const block"
+)
+
+// Level enum
+type Level uint8 // want "quasi-enum type Level violates DC-005 \\(proximity\\): const LevelLow sits between type definition and const block" "quasi-enum type Level lacks a String\\(\\) method" "quasi-enum type Level lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// LevelLow is in a single-spec block; it moves into the larger block.
+const LevelLow Level = 1 // want "quasi-enum type Level violates DC-002 \\(same const block\\): constant LevelLow is declared outside the primary const block"
+
+const (
+	LevelMedium Level = 2
+	LevelHigh   Level = 3
+	LevelMax    Level = 4
+)
+
+const (
+	unrelated = "kept"
+	LevelNone Level = 0 // want "quasi-enum type Level violates DC-002 \\(same const block\\): constant LevelNone is declared outside the primary const block"
+)
+
+// Mode enum
+type Mode uint8 // want "quasi-enum type Mode lacks a String\\(\\) method" "quasi-enum type Mode lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const ModeRead Mode = 1 // read access
+
+const ModeWrite Mode = 2 // want "quasi-enum type Mode violates DC-002 \\(same const block\\): constant ModeWrite is declared outside the primary const block"
@@ -0,0 +1,22 @@
+package fix_dc003
+
+import "time"
+
+// Status values
+const (
+	// StatusActive is the default.
+	StatusActive   Status = iota
+	StatusInactive        // inactive
+)
+
+const (
+	retries      = 3
+	KindA   Kind = 1
+	KindB   Kind = 2
+)
+
+// Unit values refer to time, whose import moves with them.
+const (
+	UnitOne Unit = Unit(time.Nanosecond)
+	UnitTwo Unit = Unit(2 * time.Nanosecond)
+)
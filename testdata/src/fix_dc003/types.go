@@ -0,0 +1,12 @@
+package fix_dc003
+
+// Test DC-003 suggested fix: constants move into the file declaring the type
+
+// Status enum
+type Status uint8 // want "quasi-enum type Status violates DC-003 \\(same file\\): type and constants must be in the same file" "quasi-enum type Status violates DC-005 \\(proximity\\): const block is in another file \\(consts.go\\)" "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// Kind enum
+type Kind uint8 // want "quasi-enum type Kind violates DC-003 \\(same file\\): type and constants must be in the same file" "quasi-enum type Kind violates DC-005 \\(proximity\\): const block is in another file \\(consts.go\\)" "quasi-enum type Kind violates DC-004 \\(exclusive const block\\): const block must contain only constants of this type" "quasi-enum type Kind lacks a String\\(\\) method" "quasi-enum type Kind lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// Unit enum
+type Unit uint8 // want "quasi-enum type Unit violates DC-003 \\(same file\\): type and constants must be in the same file" "quasi-enum type Unit violates DC-005 \\(proximity\\): const block is in another file \\(consts.go\\)" "quasi-enum type Unit lacks a String\\(\\) method" "quasi-enum type Unit lacks an UnmarshalText\\(\\[\\]byte\\) error method"
@@ -0,0 +1,32 @@
+package fix_dc004
+
+// Test DC-004 suggested fix: mixed const blocks are split per enum type
+
+type (
+	Status uint8 // want "quasi-enum type Status violates DC-004 \\(exclusive const block\\): const block must contain only constants of this type" "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method" "quasi-enum type Status is detected only by its constants"
+	Kind   uint8 // want "quasi-enum type Kind violates DC-004 \\(exclusive const block\\): const block must contain only constants of this type" "quasi-enum type Kind lacks a String\\(\\) method" "quasi-enum type Kind lacks an UnmarshalText\\(\\[\\]byte\\) error method" "quasi-enum type Kind is detected only by its constants"
+)
+
+// Shared block
+const (
+	// StatusActive is the default.
+	StatusActive Status = iota
+	StatusInactive
+	KindA Kind = iota + 10 // kind a
+	KindB
+	maxRetries                  = 3
+	StatusPending Status = iota // pending
+	timeout                     = iota * 2
+)
+
+// Mode enum
+type Mode uint8 // want "quasi-enum type Mode violates DC-004 \\(exclusive const block\\): const block must contain only constants of this type" "quasi-enum type Mode lacks a String\\(\\) method" "quasi-enum type Mode lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// A floating comment inside the block prevents the fix.
+const (
+	ModeRead Mode = 1
+	// floating
+
+	defaultMode      = "read"
+	ModeWrite   Mode = 2
+)
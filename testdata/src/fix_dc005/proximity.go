@@ -0,0 +1,55 @@
+package fix_dc005
+
+// Test DC-005 suggested fix: the const block moves directly after the type
+
+// Colors are declared before their type, which is allowed.
+const (
+	ColorRed Color = iota
+	ColorGreen
+)
+
+// Color enum
+type Color uint8 // want "quasi-enum type Color lacks a String\\(\\) method" "quasi-enum type Color lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// Shape enum
+type Shape uint8 // want "quasi-enum type Shape violates DC-005 \\(proximity\\): var defaultName sits between type definition and const block" "quasi-enum type Shape lacks a String\\(\\) method" "quasi-enum type Shape lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+var defaultName = "shape"
+
+// Shape values
+const (
+	ShapeCircle Shape = iota
+	ShapeSquare
+)
+
+// Size enum
+type Size uint8 // want "quasi-enum type Size violates DC-005 \\(proximity\\): const block is 12 lines from the type definition \\(max 10\\)" "quasi-enum type Size lacks a String\\(\\) method" "quasi-enum type Size lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// This long comment separates the type from its constants.
+//
+// Line 3
+// Line 4
+// Line 5
+// Line 6
+// Line 7
+// Line 8
+// Line 9
+// Line 10
+
+const (
+	SizeSmall Size = iota
+	SizeLarge
+)
+
+// Weight enum
+type Weight uint8 // want "quasi-enum type Weight violates DC-005 \\(proximity\\): method Weight.String sits between type definition and const block" "quasi-enum type Weight lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// String returns the weight name.
+func (w Weight) String() string {
+	return "weight"
+}
+
+const (
+	WeightLight Weight = iota
+	WeightHeavy
+)
@@ -0,0 +1,27 @@
+package foreign
+
+import "foreign/models"
+
+// Test constants of a quasi-enum type declared outside its package
+
+const Hacked models.Status = 42 // want `constant of quasi-enum type Status declared outside its package; use one of: StatusActive, StatusInactive`
+
+// StatusActive has the name of a member, but is not one
+const StatusActive models.Status = 5 // want `constant of quasi-enum type Status declared outside its package`
+
+// Untyped constants are not constants of the quasi-enum type
+const untyped = 1
+
+var one uint8 = 1
+
+func use() {
+	var s models.Status = Hacked // want `untyped constant assigned to quasi-enum type Status`
+	s = StatusActive             // want `untyped constant assigned to quasi-enum type Status`
+	s = models.StatusInactive
+	s = 3                  // want `literal value assigned to quasi-enum type Status`
+	s = models.Status(5)   // want `literal value converted to quasi-enum type Status`
+	s = models.Status(one) // want `variable converted to quasi-enum type Status`
+	_ = s
+
+	const local models.Status = 7 // want `constant of quasi-enum type Status declared outside its package`
+}
@@ -0,0 +1,58 @@
+package helper_capabilities
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Test helper-method capabilities: any accepted parse interface satisfies US6
+
+var _ json.Unmarshaler = (*JSONEnum)(nil)
+
+// JSONEnum enum, parsed by encoding/json.Unmarshaler
+type JSONEnum uint8
+
+const (
+	JSONEnumA JSONEnum = iota
+	JSONEnumB
+)
+
+func (e JSONEnum) String() string { return "json" }
+
+func (e *JSONEnum) UnmarshalJSON(data []byte) error { return errors.New("not implemented") }
+
+// FlagEnum enum, parsed by flag.Value (flag is not imported)
+type FlagEnum uint8
+
+const (
+	FlagEnumA FlagEnum = iota
+	FlagEnumB
+)
+
+func (e FlagEnum) String() string { return "flag" }
+
+func (e *FlagEnum) Set(value string) error { return errors.New("not implemented") }
+
+// BinaryEnum enum, parsed by encoding.BinaryUnmarshaler
+type BinaryEnum uint8
+
+const (
+	BinaryEnumA BinaryEnum = iota
+	BinaryEnumB
+)
+
+func (e BinaryEnum) String() string { return "binary" }
+
+func (e *BinaryEnum) UnmarshalBinary(data []byte) error { return errors.New("not implemented") }
+
+// WrongEnum enum, whose UnmarshalText has the wrong signature
+type WrongEnum uint8 // want "quasi-enum type WrongEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	WrongEnumA WrongEnum = iota
+	WrongEnumB
+)
+
+func (e WrongEnum) String() string { return "wrong" }
+
+func (e *WrongEnum) UnmarshalText(text string) error { return errors.New("not implemented") }
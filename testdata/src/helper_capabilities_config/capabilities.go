@@ -0,0 +1,36 @@
+package helper_capabilities_config
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// Test -format-interfaces, -parse-interfaces and -persist-interfaces
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+// Format implements fmt.Formatter.
+func (s Status) Format(f fmt.State, verb rune) {}
+
+func (s *Status) UnmarshalText(text []byte) error { return errors.New("not implemented") }
+
+func (s Status) Value() (driver.Value, error) { return int64(s), nil }
+
+// Kind enum
+type Kind uint8 // want "quasi-enum type Kind lacks formatting methods; implement one of: fmt.Formatter" "quasi-enum type Kind lacks parsing methods; implement one of: encoding.TextUnmarshaler" "quasi-enum type Kind lacks persistence methods; implement one of: database/sql/driver.Valuer, encoding.TextMarshaler"
+
+const (
+	KindA Kind = iota
+	KindB
+)
+
+func (k Kind) String() string { return "kind" }
+
+func (k *Kind) UnmarshalJSON(data []byte) error { return errors.New("not implemented") }
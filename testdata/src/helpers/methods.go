@@ -3,7 +3,7 @@ package helpers
 // Test US5 & US6: String() and UnmarshalText() Method Checks
 
 // Missing both methods - should warn twice
-type Priority uint8 // want "quasi-enum type Priority lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Priority lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type Priority uint8 // want "quasi-enum type Priority lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Priority lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Priority is detected only by its constants"
 
 const (
 	PriorityLow Priority = iota
@@ -11,7 +11,7 @@ const (
 )
 
 // Has String() - should warn for UnmarshalText and uint8 optimization
-type Status int // want "quasi-enum type Status uses int but has only 3 constants; consider using uint8 for memory optimization" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type Status int // want "quasi-enum type Status uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Status is detected only by its constants"
 
 const (
 	StatusActive Status = iota
@@ -33,7 +33,7 @@ func (s Status) String() string {
 }
 
 // Has both - should NOT warn for methods
-type Level uint8
+type Level uint8 // want `quasi-enum type Level is detected only by its constants; add a "// Level enum" comment to mark it explicitly`
 
 const (
 	LevelLow Level = iota
@@ -64,7 +64,7 @@ func (l *Level) UnmarshalText(text []byte) error {
 }
 
 // Has UnmarshalText but not String - should warn for String
-type Color uint8 // want "quasi-enum type Color lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it"
+type Color uint8 // want "quasi-enum type Color lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Color is detected only by its constants"
 
 const (
 	ColorRed Color = iota
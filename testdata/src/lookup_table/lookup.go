@@ -0,0 +1,67 @@
+package lookup_table
+
+// Test QOL-lookup-table: tables indexed by quasi-enum constants
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+	StatusArchived
+)
+
+func (s Status) String() string              { return statusLabels[s] }
+func (s *Status) UnmarshalText([]byte) error { return nil }
+
+var statusNames = [...]string{ // want "lookup table statusNames lacks constants of quasi-enum type Status: StatusArchived" "lookup table statusNames has length 2; constant StatusArchived of quasi-enum type Status \\(value 2\\) is out of range"
+	StatusActive:   "active",
+	StatusInactive: "inactive",
+}
+
+var statusCodes = map[Status]int{ // want "lookup table statusCodes lacks constants of quasi-enum type Status: StatusInactive"
+	StatusActive:   200,
+	StatusArchived: 410,
+}
+
+var statusWeights = []float64{ // want "lookup table statusWeights lacks constants of quasi-enum type Status: StatusActive"
+	StatusInactive: 0.5,
+	StatusArchived: 0.1,
+}
+
+// Copies of a table are reported at the table only.
+var statusNamesCopy = statusNames
+
+// Complete tables are accepted.
+var statusLabels = [...]string{
+	StatusActive:   "Active",
+	StatusInactive: "Inactive",
+	StatusArchived: "Archived",
+}
+
+var statusOrder = map[Status]int{
+	StatusActive:   1,
+	StatusInactive: 2,
+	StatusArchived: 3,
+}
+
+// Tables not keyed by enum constants are ignored.
+var plain = [...]string{0: "zero", 1: "one"}
+
+var flags [2]bool
+
+func label(s Status) string {
+	return statusLabels[s]
+}
+
+func flag(s Status) bool {
+	return flags[s] // want "index of quasi-enum type Status into array of length 2; constant StatusArchived \\(value 2\\) is out of range"
+}
+
+func name(s Status) string {
+	return statusNames[s] + statusNames[StatusActive] // want "index of quasi-enum type Status into array of length 2; constant StatusArchived \\(value 2\\) is out of range"
+}
+
+func weight(s Status) float64 {
+	return statusWeights[s] + float64(statusCodes[s]+statusOrder[s]) + float64(len(plain)+len(statusNamesCopy))
+}
@@ -0,0 +1,123 @@
+package method_coverage
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Test coverage of String(), MarshalText() and UnmarshalText() implementations
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+	StatusArchived
+)
+
+func (s Status) String() string { // want "String of quasi-enum type Status does not handle constants: StatusArchived"
+	switch s {
+	case StatusActive:
+		return "Active"
+	case StatusInactive:
+		return "in-active"
+	default:
+		return "unknown"
+	}
+}
+
+func (s *Status) UnmarshalText(text []byte) error { // want "UnmarshalText of quasi-enum type Status does not accept \"in-active\" produced by String for StatusInactive"
+	switch strings.ToLower(string(text)) {
+	case "active":
+		*s = StatusActive
+	case "inactive":
+		*s = StatusInactive
+	case "archived":
+		*s = StatusArchived
+	default:
+		return errors.New("unknown status")
+	}
+	return nil
+}
+
+// Color enum
+type Color uint8
+
+const (
+	ColorRed Color = iota
+	ColorGreen
+	ColorBlue
+)
+
+var colorNames = map[Color]string{ // want "lookup table colorNames lacks constants of quasi-enum type Color: ColorBlue"
+	ColorRed:   "red",
+	ColorGreen: "green",
+}
+
+var colorValues = map[string]Color{
+	"red":   ColorRed,
+	"green": ColorGreen,
+	"blue":  ColorBlue,
+}
+
+func (c Color) String() string { // want "String of quasi-enum type Color does not handle constants: ColorBlue"
+	return colorNames[c]
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	v, ok := colorValues[string(text)]
+	if !ok {
+		return errors.New("unknown color")
+	}
+	*c = v
+	return nil
+}
+
+// Mode enum, with a stringer-style implementation that is not analyzed
+type Mode uint8 // want "quasi-enum type Mode lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	ModeRead Mode = iota
+	ModeWrite
+)
+
+const _Mode_name = "ModeReadModeWrite"
+
+var _Mode_index = [...]uint8{0, 8, 17}
+
+func (i Mode) String() string {
+	if i >= Mode(len(_Mode_index)-1) {
+		return "Mode(" + strconv.Itoa(int(i)) + ")"
+	}
+	return _Mode_name[_Mode_index[i]:_Mode_index[i+1]]
+}
+
+// Level enum
+type Level uint8 // want "quasi-enum type Level lacks a String\\(\\) method"
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+func (l Level) MarshalText() ([]byte, error) {
+	switch l {
+	case LevelLow:
+		return []byte("low"), nil
+	case LevelHigh:
+		return []byte("high"), nil
+	}
+	return nil, errors.New("unknown level")
+}
+
+func (l *Level) UnmarshalText(text []byte) error { // want "UnmarshalText of quasi-enum type Level does not handle constants: LevelHigh"
+	switch string(text) {
+	case "low":
+		*l = LevelLow
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
@@ -3,7 +3,7 @@ package optimization
 // Test US4: uint8 Optimization Suggestion
 
 // Should suggest uint8 (int with 3 constants)
-type StatusInt int // want "quasi-enum type StatusInt uses int but has only 3 constants; consider using uint8 for memory optimization" "quasi-enum type StatusInt lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type StatusInt lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type StatusInt int // want "quasi-enum type StatusInt uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type StatusInt lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type StatusInt lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type StatusInt is detected only by its constants"
 
 const (
 	StatusActive StatusInt = iota
@@ -12,7 +12,7 @@ const (
 )
 
 // Should NOT suggest (already uint8)
-type ColorUint8 uint8 // want "quasi-enum type ColorUint8 lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type ColorUint8 lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type ColorUint8 uint8 // want "quasi-enum type ColorUint8 lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type ColorUint8 lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type ColorUint8 is detected only by its constants"
 
 const (
 	ColorRed ColorUint8 = iota
@@ -21,7 +21,7 @@ const (
 )
 
 // Should suggest uint8 (uint16 with only 3 constants)
-type LargeEnum uint16 // want "quasi-enum type LargeEnum uses uint16 but has only 3 constants; consider using uint8 for memory optimization" "quasi-enum type LargeEnum lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type LargeEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type LargeEnum uint16 // want "quasi-enum type LargeEnum uses uint16 but all values fit in uint8; consider using it for memory optimization" "quasi-enum type LargeEnum lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type LargeEnum lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
 
 const (
 	Large0 LargeEnum = iota
@@ -30,7 +30,7 @@ const (
 )
 
 // Should suggest uint8 (uint with 2 constants)
-type PriorityUint uint // want "quasi-enum type PriorityUint uses uint but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type PriorityUint lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type PriorityUint lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type PriorityUint uint // want "quasi-enum type PriorityUint uses uint but all values fit in uint8; consider using it for memory optimization" "quasi-enum type PriorityUint lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type PriorityUint lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type PriorityUint is detected only by its constants"
 
 const (
 	PriorityLow  PriorityUint = 1
@@ -38,7 +38,7 @@ const (
 )
 
 // Should suggest uint8 (int32 with 4 constants)
-type Level int32 // want "quasi-enum type Level uses int32 but has only 4 constants; consider using uint8 for memory optimization" "quasi-enum type Level lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Level lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it"
+type Level int32 // want "quasi-enum type Level uses int32 but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Level lacks a String\\(\\) method; consider using golang.org/x/tools/cmd/stringer or github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Level lacks an UnmarshalText\\(\\[\\]byte\\) error method; consider using github.com/Djarvur/go-silly-enum to generate it" "quasi-enum type Level is detected only by its constants"
 
 const (
 	Level1 Level = iota
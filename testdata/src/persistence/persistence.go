@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Test persistence check: quasi-enums stored without marshaling methods
+
+// Status enum
+type Status uint8 // want "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+// Color enum
+type Color uint8 // want "quasi-enum type Color lacks a String\\(\\) method"
+
+const (
+	ColorRed Color = iota
+	ColorGreen
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Color) MarshalText() ([]byte, error) { return []byte("red"), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Color) UnmarshalText(text []byte) error { return nil }
+
+// Kind enum
+type Kind uint8 // want "quasi-enum type Kind lacks a String\\(\\) method" "quasi-enum type Kind lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	KindA Kind = iota
+	KindB
+)
+
+// Value implements driver.Valuer.
+func (k Kind) Value() (driver.Value, error) { return int64(k), nil }
+
+// Scan implements sql.Scanner.
+func (k *Kind) Scan(src any) error { return errors.New("not implemented") }
+
+// Level enum
+type Level uint8
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+func (l Level) String() string { return "low" }
+
+// MarshalYAML does not implement the yaml Marshaler: its result is not any.
+func (l Level) MarshalYAML() (string, error) { return l.String(), nil }
+
+// UnmarshalYAML implements the gopkg.in/yaml.v2 Unmarshaler.
+func (l *Level) UnmarshalYAML(unmarshal func(any) error) error { return nil }
+
+type Settings struct {
+	Level Level `yaml:"level"` // want "quasi-enum type Level persisted via yaml tag of field Level lacks MarshalYAML/MarshalText; it is stored as its underlying value"
+}
+
+type Order struct {
+	ID     int    `json:"id"`
+	Status Status `json:"status"` // want "quasi-enum type Status persisted via json tag of field Status lacks MarshalJSON/MarshalText and UnmarshalJSON/UnmarshalText; it is stored as its underlying value"
+	Color  Color  `json:"color" yaml:"color"`
+	Kind   Kind   `db:"kind" json:"-"`
+	Other  Status `db:"other"` // want "quasi-enum type Status persisted via db tag of field Other lacks Value and Scan; it is stored as its underlying value"
+	Hidden Status `json:"-"`
+	Tags   []Status `yaml:"tags,omitempty"` // want "quasi-enum type Status persisted via yaml tag of field Tags lacks MarshalYAML/MarshalText and UnmarshalYAML/UnmarshalText; it is stored as its underlying value"
+}
+
+type Event struct {
+	Status Status
+	Kinds  []Kind
+	Color  *Color
+}
+
+func save(w io.Writer, o Order, e Event) {
+	_, _ = json.Marshal(o) // want "quasi-enum type Status persisted via json.Marshal lacks MarshalJSON/MarshalText; it is stored as its underlying value"
+	_, _ = json.MarshalIndent(&e, "", " ") // want "quasi-enum type Status persisted via json.MarshalIndent lacks MarshalJSON/MarshalText; it is stored as its underlying value" "quasi-enum type Kind persisted via json.MarshalIndent lacks MarshalJSON/MarshalText; it is stored as its underlying value"
+	_ = json.NewEncoder(w).Encode(map[string]Color{"c": ColorRed})
+	_ = gob.NewEncoder(w).Encode(e.Status) // want "quasi-enum type Status persisted via \\(\\*gob.Encoder\\).Encode lacks GobEncode/MarshalBinary/MarshalText; it is stored as its underlying value"
+}
+
+func load(data []byte, r io.Reader, rows *sql.Rows) {
+	var s Status
+	_ = json.Unmarshal(data, &s) // want "quasi-enum type Status loaded via json.Unmarshal lacks UnmarshalJSON/UnmarshalText; it is read from its underlying value"
+
+	var id int
+	var k Kind
+	_ = gob.NewDecoder(r).Decode(&s) // want "quasi-enum type Status loaded via \\(\\*gob.Decoder\\).Decode lacks GobDecode/UnmarshalBinary/UnmarshalText; it is read from its underlying value"
+
+	_ = rows.Scan(&id, &s, &k) // want "quasi-enum type Status loaded via \\(\\*sql.Rows\\).Scan lacks Scan; it is read from its underlying value"
+}
@@ -0,0 +1,39 @@
+package proximity_config
+
+// Test DC-005 with -proximity-lines=3 and -proximity-allow-methods
+
+// Weight enum
+type Weight uint8 // want "quasi-enum type Weight lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// String returns the weight name.
+func (w Weight) String() string {
+	return "weight"
+}
+
+const (
+	WeightLight Weight = iota
+	WeightHeavy
+)
+
+// Size enum
+type Size uint8 // want "quasi-enum type Size violates DC-005 \\(proximity\\): const block is 5 lines from the type definition \\(max 3\\)" "quasi-enum type Size lacks a String\\(\\) method" "quasi-enum type Size lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// Line 1
+// Line 2
+// Line 3
+
+const (
+	SizeSmall Size = iota
+	SizeLarge
+)
+
+// Color enum
+type Color uint8 // want "quasi-enum type Color violates DC-005 \\(proximity\\): func helper sits between type definition and const block" "quasi-enum type Color lacks a String\\(\\) method" "quasi-enum type Color lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+// helper is not a method of Color, so it still separates the declarations.
+func helper() {}
+
+const (
+	ColorRed Color = iota
+	ColorGreen
+)
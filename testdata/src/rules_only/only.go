@@ -0,0 +1,21 @@
+package rules_only
+
+// Test -only=US1-*,DC-*: quality-of-life and US2/US3 findings are filtered out
+
+// enum
+type Status int // want "quasi-enum type Status violates DC-001 \\(minimum 2 constants\\): must have at least 2 constants"
+
+const StatusActive Status = 1
+
+const untypedValue = 3
+
+func testOnly() {
+	var s1 Status = 5            // want "literal value assigned to quasi-enum type Status"
+	var s2 Status = untypedValue // filtered: US2-untyped-constant
+	var x int = 1
+	s3 := Status(x) // filtered: US3-variable-conversion
+
+	_ = s1
+	_ = s2
+	_ = s3
+}
@@ -0,0 +1,24 @@
+package rules_skip
+
+// Test -skip=QOL-*,us3-*: quality-of-life findings and US3 are filtered out (globs are case-insensitive)
+
+// Status enum
+type Status int
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+const untypedValue = 3
+
+func testSkip() {
+	var s1 Status = 5            // want "literal value assigned to quasi-enum type Status"
+	var s2 Status = untypedValue // want "untyped constant assigned to quasi-enum type Status"
+	var x int = 1
+	s3 := Status(x) // filtered: US3-variable-conversion
+
+	_ = s1
+	_ = s2
+	_ = s3
+}
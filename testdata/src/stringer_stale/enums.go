@@ -0,0 +1,24 @@
+package stringer_stale
+
+// Test detection of stale stringer output
+
+//go:generate stringer -type=Status,Color
+
+// Status enum
+type Status uint8 // want "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+	// StatusPending was added without re-running stringer.
+	StatusPending
+	StatusArchived
+)
+
+// Color enum
+type Color uint8 // want "quasi-enum type Color lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+
+const (
+	ColorRed Color = iota
+	ColorGreen
+)
@@ -0,0 +1,70 @@
+package struct_layout
+
+// Test QOL-struct-layout: structs shrinking with compact enums and reordered fields
+
+// Status enum
+type Status int // want "quasi-enum type Status uses int but all values fit in uint8; consider using it for memory optimization"
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+// Kind enum
+type Kind uint8
+
+const (
+	KindA Kind = iota
+	KindB
+)
+
+// Record mixes an enum between wider fields.
+type Record struct { // want "struct Record takes 32 bytes but could take 24 with Status as uint8 and its fields reordered"
+	// ID identifies the record.
+	ID     int64
+	Active bool // whether the record is live
+	Count  int64
+	State  Status
+}
+
+// Packed only gains from the compact enum.
+type Packed struct { // want "struct Packed takes 16 bytes but could take 2 with Status as uint8"
+	State Status
+	Flag  bool
+}
+
+// Loose gains from reordering alone.
+type Loose struct { // want "struct Loose takes 32 bytes but could take 24 with its fields reordered"
+	A    bool
+	N, M int32
+	P    *int
+	K    Kind
+}
+
+// Tight is already optimal.
+type Tight struct {
+	N int64
+	K Kind
+	F bool
+}
+
+// Positional is built from an unkeyed literal, so no fix is offered.
+type Positional struct { // want "struct Positional takes 24 bytes but could take 16 with its fields reordered"
+	K Kind
+	N int64
+	L Kind
+}
+
+var _ = Positional{KindA, 1, KindB}
+
+// Plain has no enum fields.
+type Plain struct {
+	A bool
+	N int64
+	B bool
+}
+
+func (s Status) String() string              { return "" }
+func (s *Status) UnmarshalText([]byte) error { return nil }
+func (k Kind) String() string                { return "" }
+func (k *Kind) UnmarshalText([]byte) error   { return nil }
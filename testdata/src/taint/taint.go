@@ -0,0 +1,168 @@
+package taint
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Test untrusted input reaching quasi-enum conversions
+
+// Status enum
+type Status uint8
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+	StatusPending
+)
+
+// Direct conversion of a parsed form value
+func fromForm(r *http.Request) Status {
+	n, _ := strconv.Atoi(r.FormValue("status"))
+	return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation: strconv.Atoi \(line 22\) -> conversion to Status \(line 23\)`
+}
+
+// Arithmetic does not validate
+func shifted(s string) Status {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return StatusActive
+	}
+	return Status(n - 1) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation: strconv.Atoi \(line 28\) -> arithmetic \(line 32\) -> conversion to Status \(line 32\)`
+}
+
+// Range check before the conversion
+func checked(s string) (Status, bool) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > int(StatusPending) {
+		return StatusActive, false
+	}
+	return Status(n), true
+}
+
+// Comparison on the converted value does not dominate the conversion
+func checkedAfter(s string) bool {
+	n, _ := strconv.ParseUint(s, 10, 8)
+	st := Status(n) // want `untrusted value from strconv.ParseUint is converted to quasi-enum type Status without validation`
+	return st <= StatusPending
+}
+
+type request struct {
+	Status int
+}
+
+// Decoded payloads are untrusted
+func fromJSON(data []byte) Status {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return StatusActive
+	}
+	return Status(req.Status) // want `untrusted value from json.Unmarshal is converted to quasi-enum type Status without validation: json.Unmarshal \(line 58\) -> load \(line 61\) -> conversion to Status \(line 61\)`
+}
+
+// Values read before decoding are not tainted
+func beforeDecode(data []byte) Status {
+	var req request
+	s := Status(req.Status)
+	_ = json.Unmarshal(data, &req)
+	return s
+}
+
+// Trusted values are not reported
+func trusted(n int) Status {
+	return Status(n)
+}
+
+// One-sided checks do not validate
+func nonNegative(s string) Status {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return StatusActive
+	}
+	return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation`
+}
+
+// Comparisons with other bounds do not validate
+func belowTen(s string) Status {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 10 {
+		return StatusActive
+	}
+	return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation`
+}
+
+// Equality with a constant validates
+func pending(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if int(StatusPending) == n {
+		return Status(n)
+	}
+	return StatusActive
+}
+
+// Decoding in the loop condition taints the loads of the loop body
+func decodeAll(dec *json.Decoder) []Status {
+	var req request
+	var out []Status
+	for dec.Decode(&req) == nil {
+		out = append(out, Status(req.Status)) // want `untrusted value from \(\*json.Decoder\).Decode is converted to quasi-enum type Status without validation`
+	}
+	return out
+}
+
+func note(string) {}
+
+// Equality checked on a branch that rejoins does not validate
+func unguardedEqual(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if n == 0 {
+		note("zero")
+	}
+	return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation`
+}
+
+// Inequality validates only on its false edge
+func notEqual(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if n != int(StatusPending) {
+		note("other")
+	}
+	return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation`
+}
+
+func notEqualRejected(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if n != int(StatusPending) {
+		return StatusActive
+	}
+	return Status(n)
+}
+
+// Converting on the out-of-range edge does not validate
+func inverted(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if n >= 0 {
+		if n <= int(StatusPending) {
+			return StatusActive
+		}
+		return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation`
+	}
+	return StatusActive
+}
+
+func outOfRange(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if n < 0 || n > int(StatusPending) {
+		return Status(n) // want `untrusted value from strconv.Atoi is converted to quasi-enum type Status without validation`
+	}
+	return StatusActive
+}
+
+func inRange(s string) Status {
+	n, _ := strconv.Atoi(s)
+	if n >= 0 && n <= int(StatusPending) {
+		return Status(n)
+	}
+	return StatusActive
+}
@@ -0,0 +1,25 @@
+package main // want package:"0 unused, 1 referenced" `constant example.com/unused/lib.LevelMax of quasi-enum type Level \(lib.go:11\) is never referenced by this program`
+
+import (
+	"fmt"
+
+	"example.com/unused/lib"
+)
+
+// Test unused quasi-enum constants of a main package and its dependencies
+
+// Mode enum
+type Mode uint8
+
+const (
+	ModeRead  Mode = iota
+	ModeWrite      // want `constant ModeWrite of quasi-enum type Mode is never referenced`
+)
+
+func (m Mode) String() string { return "mode" }
+
+func (m *Mode) UnmarshalText(text []byte) error { return nil }
+
+func main() {
+	fmt.Println(lib.LevelLow, lib.Default(), ModeRead)
+}
@@ -0,0 +1,33 @@
+package lib // want package:"2 unused, 0 referenced"
+
+// Test unused quasi-enum constants of a library package
+
+// Level enum
+type Level uint8
+
+const (
+	LevelLow  Level = iota // used by the main package
+	LevelHigh              // used by this package
+	LevelMax               // only used by the methods of Level
+
+	levelInternal             // want `constant levelInternal of quasi-enum type Level is never referenced`
+	levelUsed                 // used by this package
+	levelLast = levelInternal // want `constant levelLast of quasi-enum type Level is never referenced`
+)
+
+func (l Level) String() string { return "level" }
+
+func (l *Level) UnmarshalText(text []byte) error { return nil }
+
+// IsMax reports whether l is the highest level.
+func (l Level) IsMax() bool { return l == LevelMax }
+
+// Default returns the default level.
+func Default() Level {
+	return LevelHigh
+}
+
+// Internal reports whether l is an internal level.
+func Internal(l Level) bool {
+	return l == levelUsed
+}
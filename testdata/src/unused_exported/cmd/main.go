@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/unused_exported/lib"
+)
+
+func main() {
+	fmt.Println(lib.LevelLow)
+}
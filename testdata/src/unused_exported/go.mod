@@ -0,0 +1,3 @@
+module example.com/unused_exported
+
+go 1.24
@@ -0,0 +1,17 @@
+package lib
+
+// Test -unused-allow-exported: exported constants of library packages are kept
+
+// Level enum
+type Level uint8
+
+const (
+	LevelLow  Level = iota // used by the main package
+	LevelHigh              // not used, but exported
+
+	levelInternal // want `constant levelInternal of quasi-enum type Level is never referenced`
+)
+
+func (l Level) String() string { return "level" }
+
+func (l *Level) UnmarshalText(text []byte) error { return nil }
@@ -0,0 +1,81 @@
+package enums
+
+// Test functions returning quasi-enum values converted without validation
+
+// Status enum
+type Status uint8 // want `quasi-enum type Status lacks a String\(\) method` `quasi-enum type Status lacks an UnmarshalText\(\[\]byte\) error method`
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+	StatusPending
+)
+
+// ToStatus converts without validation.
+func ToStatus(i uint8) Status { // want ToStatus:"unvalidated results 0:Status"
+	return Status(i)
+}
+
+// Parse returns an unvalidated conversion along with an error.
+func Parse(i uint8) (Status, error) { // want Parse:"unvalidated results 0:Status"
+	return Status(i), nil
+}
+
+// Wrap returns the result of ToStatus.
+func Wrap(i uint8) Status { // want Wrap:"unvalidated results 0:Status"
+	return ToStatus(i)
+}
+
+// Checked validates before converting.
+func Checked(i uint8) Status {
+	if i > uint8(StatusPending) {
+		return StatusActive
+	}
+	return Status(i)
+}
+
+// Local converts through a local variable.
+func Local(i uint8) Status { // want Local:"unvalidated results 0:Status"
+	s := Status(i) // want `variable converted to quasi-enum type Status; use one of: StatusActive, StatusInactive, StatusPending`
+	return s
+}
+
+// LocalChecked compares the local variable before returning it.
+func LocalChecked(i uint8) Status {
+	s := Status(i) // want `variable converted to quasi-enum type Status; use one of: StatusActive, StatusInactive, StatusPending`
+	if s > StatusPending {
+		return StatusActive
+	}
+	return s
+}
+
+// LocalLogged compares the local variable without guarding the return.
+func LocalLogged(i uint8) Status { // want LocalLogged:"unvalidated results 0:Status"
+	s := Status(i) // want `variable converted to quasi-enum type Status; use one of: StatusActive, StatusInactive, StatusPending`
+	if s > StatusPending {
+		println("out of range")
+	}
+	return s
+}
+
+// Lookup returns its unvalidated result second.
+func Lookup(i uint8) (int, Status) { // want Lookup:"unvalidated results 1:Status"
+	return int(i), Status(i)
+}
+
+// Forward returns the results of Lookup.
+func Forward(i uint8) (int, Status) { // want Forward:"unvalidated results 1:Status"
+	return Lookup(i)
+}
+
+// Second returns the second result of Lookup through a local variable.
+func Second(i uint8) Status { // want Second:"unvalidated results 0:Status"
+	_, s := Lookup(i)
+	return s
+}
+
+// First returns the results of Parse through local variables.
+func First(i uint8) (Status, error) { // want First:"unvalidated results 0:Status"
+	s, err := Parse(i)
+	return s, err
+}
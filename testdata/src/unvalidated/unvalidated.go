@@ -0,0 +1,113 @@
+package unvalidated
+
+import (
+	"encoding/json"
+
+	"unvalidated/enums"
+)
+
+// Test uses of unvalidated results from another package
+
+func switchDirect(i uint8) string {
+	switch enums.ToStatus(i) { // want `result of enums.ToStatus may be outside quasi-enum type Status \(converted without validation\) and is switched on without a default case`
+	case enums.StatusActive:
+		return "active"
+	case enums.StatusInactive:
+		return "inactive"
+	}
+	return ""
+}
+
+func switchVar(i uint8) string {
+	s, err := enums.Parse(i)
+	if err != nil {
+		return ""
+	}
+	switch s { // want `result of enums.Parse may be outside quasi-enum type Status`
+	case enums.StatusActive:
+		return "active"
+	}
+	return ""
+}
+
+func switchWrapped(i uint8) string {
+	s := enums.Wrap(i)
+	switch s { // want `result of enums.Wrap may be outside quasi-enum type Status`
+	case enums.StatusActive:
+		return "active"
+	}
+	return ""
+}
+
+func switchDefault(i uint8) string {
+	switch enums.ToStatus(i) {
+	case enums.StatusActive:
+		return "active"
+	default:
+		return "unknown"
+	}
+}
+
+func switchChecked(i uint8) string {
+	s := enums.ToStatus(i)
+	if s > enums.StatusPending {
+		return ""
+	}
+	switch s {
+	case enums.StatusActive:
+		return "active"
+	}
+	return ""
+}
+
+func switchValidated(i uint8) string {
+	switch enums.Checked(i) {
+	case enums.StatusActive:
+		return "active"
+	}
+	return ""
+}
+
+func persist(i uint8) ([]byte, error) {
+	s := enums.ToStatus(i)
+	return json.Marshal(s) // want `result of enums.ToStatus may be outside quasi-enum type Status \(converted without validation\) and is persisted via json.Marshal` `quasi-enum type Status persisted via json.Marshal lacks MarshalJSON/MarshalText; it is stored as its underlying value`
+}
+
+func note(string) {}
+
+func switchLogged(i uint8) string {
+	s := enums.ToStatus(i)
+	if s > enums.StatusPending {
+		note("out of range")
+	}
+	switch s { // want `result of enums.ToStatus may be outside quasi-enum type Status`
+	case enums.StatusActive:
+		return "active"
+	}
+	return ""
+}
+
+func switchGuarded(i uint8) string {
+	s := enums.ToStatus(i)
+	if s <= enums.StatusPending {
+		switch s {
+		case enums.StatusActive:
+			return "active"
+		}
+	}
+	return ""
+}
+
+func switchNestedCheck(i uint8, strict bool) string {
+	s := enums.ToStatus(i)
+	if strict {
+		if s > enums.StatusPending {
+			return ""
+		}
+	}
+	switch s { // want `result of enums.ToStatus may be outside quasi-enum type Status`
+	case enums.StatusActive:
+		return "active"
+	}
+	return ""
+}
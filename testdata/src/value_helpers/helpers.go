@@ -0,0 +1,112 @@
+package value_helpers
+
+import (
+	"fmt"
+)
+
+// Test QOL-value-helpers and QOL-stale-value-helpers
+
+// Status enum
+type Status uint8 // want "quasi-enum type Status lacks IsValid\\(\\) bool, Values\\(\\) \\[\\]Status, All\\(\\) iter.Seq\\[Status\\]; generate them to validate and list its values"
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+	StatusDefault = StatusActive
+)
+
+// Kind enum
+type Kind uint8 // want "quasi-enum type Kind lacks Values\\(\\) \\[\\]Kind, All\\(\\) iter.Seq\\[Kind\\]; generate them to validate and list its values"
+
+const (
+	KindA Kind = iota
+	KindB
+	KindC
+)
+
+func (k Kind) IsValid() bool { // want "IsValid of quasi-enum type Kind rejects constants: KindC; update or regenerate it"
+	switch k {
+	case KindA, KindB:
+		return true
+	}
+	return false
+}
+
+// Level enum
+type Level uint8
+
+const (
+	LevelLow Level = iota
+	LevelMid
+	LevelHigh
+)
+
+var allLevels = []Level{LevelLow, LevelMid}
+
+func (l Level) IsValid() bool { // want "IsValid of quasi-enum type Level rejects constants: LevelHigh; update or regenerate it"
+	return l >= LevelLow && l <= LevelMid
+}
+
+func (Level) Values() []Level { // want "Values of quasi-enum type Level omits constants: LevelHigh; update or regenerate it"
+	return allLevels
+}
+
+// Mode enum
+type Mode uint8
+
+const (
+	ModeRead Mode = iota
+	ModeWrite
+)
+
+func (m Mode) IsValid() bool {
+	return !(m < ModeRead || int(m) > int(ModeWrite))
+}
+
+func (Mode) Values() []Mode {
+	return []Mode{ModeRead, ModeWrite}
+}
+
+// Grade enum
+type Grade uint8
+
+const (
+	GradeA Grade = iota
+	GradeB
+	GradeC
+	GradeLegacy
+)
+
+// IsValid accepts GradeLegacy before the range check: its form is not
+// understood, so it is not reported.
+func (g Grade) IsValid() bool {
+	if g == GradeLegacy {
+		return true
+	}
+	return g >= GradeA && g <= GradeC
+}
+
+func (Grade) Values() []Grade {
+	return []Grade{GradeA, GradeB, GradeC, GradeLegacy}
+}
+
+// Über enum
+type Über uint8 // want "quasi-enum type Über lacks IsValid\\(\\) bool, Values\\(\\) \\[\\]Über, All\\(\\) iter.Seq\\[Über\\]; generate them to validate and list its values"
+
+const (
+	ÜberA Über = iota
+	ÜberB
+)
+
+func (s Status) String() string              { return fmt.Sprint(uint8(s)) }
+func (s *Status) UnmarshalText([]byte) error { return nil }
+func (k Kind) String() string                { return "" }
+func (k *Kind) UnmarshalText([]byte) error   { return nil }
+func (l Level) String() string               { return "" }
+func (l *Level) UnmarshalText([]byte) error  { return nil }
+func (m Mode) String() string                { return "" }
+func (m *Mode) UnmarshalText([]byte) error   { return nil }
+func (g Grade) String() string               { return "" }
+func (g *Grade) UnmarshalText([]byte) error  { return nil }
+func (ü Über) String() string                { return "" }
+func (ü *Über) UnmarshalText([]byte) error   { return nil }
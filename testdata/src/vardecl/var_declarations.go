@@ -3,7 +3,7 @@ package vardecl
 // Test variable declaration edge cases
 
 // Status enum
-type Status int // want "quasi-enum type Status uses int but has only 3 constants; consider using uint8 for memory optimization" "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type Status int // want "quasi-enum type Status uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Status lacks a String\\(\\) method" "quasi-enum type Status lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	StatusActive Status = iota
@@ -12,7 +12,7 @@ const (
 )
 
 // Priority enum
-type Priority int // want "quasi-enum type Priority uses int but has only 2 constants; consider using uint8 for memory optimization" "quasi-enum type Priority lacks a String\\(\\) method" "quasi-enum type Priority lacks an UnmarshalText\\(\\[\\]byte\\) error method"
+type Priority int // want "quasi-enum type Priority uses int but all values fit in uint8; consider using it for memory optimization" "quasi-enum type Priority lacks a String\\(\\) method" "quasi-enum type Priority lacks an UnmarshalText\\(\\[\\]byte\\) error method"
 
 const (
 	PriorityLow  Priority = 1
@@ -41,13 +41,18 @@ func testVarDeclEdgeCases() {
 
 	// Valid: conversion from constant
 	const validConst Status = StatusActive
-	var s6 Status = validConst // want "untyped constant assigned to quasi-enum type Status"
+	var s6 Status = validConst
 	_ = s6
 
 	// Invalid: conversion from untyped constant
 	const untypedConst = 7
 	var s7 Status = untypedConst // want "untyped constant assigned to quasi-enum type Status"
 	_ = s7
+
+	// Invalid: typed constant sharing a member's value
+	const sameValue Status = 1
+	var s8 Status = sameValue // want "untyped constant assigned to quasi-enum type Status"
+	_ = s8
 }
 
 func testVarDeclWithConversion() {
@@ -111,3 +111,209 @@ func TestCallExpressions(t *testing.T) {
 	testdata := filepath.Join(wd, "..", "..", "testdata")
 	analysistest.Run(t, testdata, analyzer.Analyzer, "call_expr")
 }
+
+func TestRuleFilters(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+
+	setFlag(t, "only", "US1-*,DC-*")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "rules_only")
+
+	setFlag(t, "only", "")
+	setFlag(t, "skip", "QOL-*,us3-*")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "rules_skip")
+}
+
+// setFlag sets an analyzer flag for the duration of the test.
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+	old := analyzer.Analyzer.Flags.Lookup(name).Value.String()
+	if err := analyzer.Analyzer.Flags.Set(name, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := analyzer.Analyzer.Flags.Set(name, old); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestDC002_SameConstBlockFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "fix_dc002")
+}
+
+func TestDC005_ProximityFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "fix_dc005")
+
+	setFlag(t, "proximity-lines", "3")
+	setFlag(t, "proximity-allow-methods", "true")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "proximity_config")
+}
+
+func TestDC004_ExclusiveBlockFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "fix_dc004")
+}
+
+func TestDC003_SameFileFix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "fix_dc003")
+}
+
+func TestPersistence(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "persistence")
+}
+
+func TestMethodCoverage(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "method_coverage")
+}
+
+func TestStaleStringer(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "stringer_stale")
+}
+
+func TestHelperCapabilities(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "helper_capabilities")
+
+	setFlag(t, "format-interfaces", "fmt.Formatter")
+	setFlag(t, "parse-interfaces", "encoding.TextUnmarshaler")
+	setFlag(t, "persist-interfaces", "database/sql/driver.Valuer,encoding.TextMarshaler")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "helper_capabilities_config")
+}
+
+func TestUnvalidatedResults(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "unvalidated/...")
+}
+
+func TestUnusedConstants(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+
+	setFlag(t, "unused-constants", "true")
+	analysistest.Run(t, filepath.Join(testdata, "src", "unused"), analyzer.Analyzer, "./...")
+
+	setFlag(t, "unused-allow-exported", "true")
+	analysistest.Run(t, filepath.Join(testdata, "src", "unused_exported"), analyzer.Analyzer, "./...")
+}
+
+func TestForeignConstants(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "foreign/...")
+}
+
+func TestAliases(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "aliases/...")
+}
+
+func TestEnumMarker(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "enum_marker")
+}
+
+func TestCompactType(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "compact_type")
+}
+
+func TestStructLayout(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "struct_layout")
+}
+
+func TestLookupTable(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "lookup_table")
+}
+
+func TestValueHelpers(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	setFlag(t, "value-helpers", "true")
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer.Analyzer, "value_helpers")
+}
+
+func TestTaint(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+	analysistest.Run(t, testdata, analyzer.TaintAnalyzer, "taint")
+}